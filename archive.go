@@ -0,0 +1,239 @@
+package pagereader
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Paper size in inches
+type PaperSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	PaperLetter = PaperSize{Width: 8.5, Height: 11}
+	PaperLegal  = PaperSize{Width: 8.5, Height: 14}
+	PaperA4     = PaperSize{Width: 8.27, Height: 11.69}
+	PaperA3     = PaperSize{Width: 11.69, Height: 16.54}
+)
+
+// Margins in inches
+type Margins struct {
+	Top    float64
+	Bottom float64
+	Left   float64
+	Right  float64
+}
+
+type PDFOptions struct {
+	Paper           PaperSize // default is Letter
+	Margins         *Margins  // nil use chrome default margins(about 0.4 inches)
+	Landscape       bool
+	PrintBackground bool
+	Scale           float64 // default 1
+}
+
+// ArchiveOptions Open will write archives of the page into Dir, file name is made from URL, see ArchiveName
+type ArchiveOptions struct {
+	Dir        string
+	PDF        bool
+	PDFOptions PDFOptions
+	MHTML      bool
+	TimeSuffix bool // Append Open time to file names, existing files are never overwritten, see archiveFileName
+}
+
+func pdfTask(opts PDFOptions, buf *[]byte) chromedp.ActionFunc {
+	return func(ctx context.Context) (err error) {
+		params := page.PrintToPDF().
+			WithLandscape(opts.Landscape).
+			WithPrintBackground(opts.PrintBackground)
+		if opts.Paper.Width > 0 && opts.Paper.Height > 0 {
+			params = params.WithPaperWidth(opts.Paper.Width).WithPaperHeight(opts.Paper.Height)
+		}
+		if opts.Margins != nil {
+			params = params.
+				WithMarginTop(opts.Margins.Top).
+				WithMarginBottom(opts.Margins.Bottom).
+				WithMarginLeft(opts.Margins.Left).
+				WithMarginRight(opts.Margins.Right)
+		}
+		if opts.Scale > 0 {
+			params = params.WithScale(opts.Scale)
+		}
+		*buf, _, err = params.Do(ctx)
+		return
+	}
+}
+
+func mhtmlTask(data *string) chromedp.ActionFunc {
+	return func(ctx context.Context) (err error) {
+		*data, err = page.CaptureSnapshot().WithFormat(page.CaptureSnapshotFormatMhtml).Do(ctx)
+		return
+	}
+}
+
+// PDF Print current page to PDF, only works in headless mode
+func (pr *PageReader) PDF(ctx context.Context, opts PDFOptions) ([]byte, error) {
+	var buf []byte
+	err := pr.RunTasks(ctx, "PDF", pr.Config.Timeout, chromedp.Tasks{pdfTask(opts, &buf)})
+	return buf, err
+}
+
+// MHTML Capture current page as a single-file MHTML snapshot
+func (pr *PageReader) MHTML(ctx context.Context) (string, error) {
+	var data string
+	err := pr.RunTasks(ctx, "MHTML", pr.Config.Timeout, chromedp.Tasks{mhtmlTask(&data)})
+	return data, err
+}
+
+// SavePDF Print current page to PDF file
+func (pr *PageReader) SavePDF(ctx context.Context, filename string, opts PDFOptions) error {
+	buf, err := pr.PDF(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return writeFile(filename, buf)
+}
+
+// SaveMHTML Save current page to MHTML file
+func (pr *PageReader) SaveMHTML(ctx context.Context, filename string) error {
+	data, err := pr.MHTML(ctx)
+	if err != nil {
+		return err
+	}
+	return writeFile(filename, []byte(data))
+}
+
+// SetArchive Open will write the page archives into opts.Dir, nil to disable
+func (pr *PageReader) SetArchive(opts *ArchiveOptions) *PageReader {
+	pr.Config.Archive = opts
+	return pr
+}
+
+// ArchiveErrors Errors of failed archive formats, other formats are still written
+type ArchiveErrors []error
+
+func (e ArchiveErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "pagereader: archive failed, " + strings.Join(messages, "; ")
+}
+
+// Is Whether any of the errors matches target
+func (e ArchiveErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// archive Write archives of current page, return file paths. Formats are captured independently, so a failed PDF
+// doesn't prevent MHTML.
+func (pr *PageReader) archive(ctx context.Context, opts ArchiveOptions) ([]string, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("pagereader: archive dir is empty")
+	}
+	name := archiveFileName(pr.URL, opts.TimeSuffix, time.Now())
+	files := make([]string, 0)
+	errs := make(ArchiveErrors, 0)
+	save := func(ext string, capture chromedp.Action, data func() []byte) {
+		err := chromedp.Run(ctx, pr.ChromeDP.RunWithTimeOut(&ctx, pr.Config.Timeout, chromedp.Tasks{capture}))
+		filename := filepath.Join(opts.Dir, name+ext)
+		if err == nil && opts.TimeSuffix {
+			err = createFile(filename, data())
+		} else if err == nil {
+			err = writeFile(filename, data())
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", strings.TrimPrefix(ext, "."), err))
+			return
+		}
+		files = append(files, filename)
+	}
+	if opts.PDF {
+		var buf []byte
+		save(".pdf", pdfTask(opts.PDFOptions, &buf), func() []byte { return buf })
+	}
+	if opts.MHTML {
+		var data string
+		save(".mhtml", mhtmlTask(&data), func() []byte { return []byte(data) })
+	}
+	if len(errs) > 0 {
+		return files, errs
+	}
+	return files, nil
+}
+
+// archiveFileName Archive name of the URL, with time if timeSuffix,
+// e.g. www.amazon.com_dp_B092M62439_1a2b3c4d5e6f_20220124150405
+func archiveFileName(rawURL string, timeSuffix bool, t time.Time) string {
+	name := ArchiveName(rawURL)
+	if timeSuffix {
+		name += "_" + t.Format("20060102150405")
+	}
+	return name
+}
+
+var archiveNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// ArchiveName Deterministic file name (without extension) for the URL, e.g. www.amazon.com_dp_B092M62439_1a2b3c4d5e6f.
+// Archives of the same page overwrite each other unless ArchiveOptions.TimeSuffix is on.
+func ArchiveName(rawURL string) string {
+	sum := sha1.Sum([]byte(rawURL))
+	hash := hex.EncodeToString(sum[:])[:12]
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		name = u.Host + u.Path
+	}
+	name = strings.Trim(archiveNameInvalidChars.ReplaceAllString(name, "_"), "_.")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	if name == "" {
+		return hash
+	}
+	return name + "_" + hash
+}
+
+func writeFile(filename string, data []byte) error {
+	if dir := filepath.Dir(filename); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// createFile Same as writeFile but fails with fs.ErrExist if the file exists
+func createFile(filename string, data []byte) error {
+	if dir := filepath.Dir(filename); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package pagereader
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveName(t *testing.T) {
+	name := ArchiveName("https://www.amazon.com/dp/B092M62439?th=1")
+	if name != ArchiveName("https://www.amazon.com/dp/B092M62439?th=1") {
+		t.Errorf("archive name is not deterministic")
+	}
+	if name[:len(name)-13] != "www.amazon.com_dp_B092M62439" {
+		t.Errorf("unexpected archive name %s", name)
+	}
+	if name == ArchiveName("https://www.amazon.com/dp/B092M62439?th=2") {
+		t.Errorf("different URLs should get different names")
+	}
+}
+
+func TestArchiveFileName(t *testing.T) {
+	const rawURL = "https://example.com/page"
+	now := time.Date(2022, 1, 24, 15, 4, 5, 0, time.UTC)
+	if name := archiveFileName(rawURL, false, now); name != ArchiveName(rawURL) {
+		t.Errorf("archive file name should be deterministic without time suffix, got %s", name)
+	}
+	if name := archiveFileName(rawURL, true, now); name != ArchiveName(rawURL)+"_20220124150405" {
+		t.Errorf("unexpected archive file name %s", name)
+	}
+}
+
+func TestCreateFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "archive", "page.mhtml")
+	if err := createFile(filename, []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := createFile(filename, []byte("b")); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("existing archive should not be overwritten, error: %v", err)
+	}
+	if b, _ := os.ReadFile(filename); string(b) != "a" {
+		t.Errorf("unexpected content: %s", b)
+	}
+}
+
+func TestPageReader_ArchiveErrors(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.URL = "https://example.com/"
+	files, err := pr.archive(noBrowserContext(t), ArchiveOptions{Dir: t.TempDir(), PDF: true, MHTML: true})
+	var errs ArchiveErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || len(files) != 0 {
+		t.Fatalf("MHTML should be captured after PDF failed, files: %v, error: %v", files, err)
+	}
+	if !errors.Is(err, errNoBrowser) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -6,14 +6,18 @@ import (
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
 	"github.com/chromedp/chromedp"
-	"log"
+	"sync"
 	"time"
 )
 
 type ChromeDP struct {
 	httpHeaders          network.Headers
 	ExecAllocatorOptions []chromedp.ExecAllocatorOption
+	scripts              []Script
+	injectedScriptsMu    sync.Mutex
+	injectedScripts      map[target.ID]*targetScripts // Registered scripts per target
 }
 
 func (c *ChromeDP) SetHTTPHeader(k, v string) *ChromeDP {
@@ -28,7 +32,7 @@ func (c *ChromeDP) SetHTTPHeaders(kv map[string]string) *ChromeDP {
 	return c
 }
 
-func (c ChromeDP) HttpHeaders() network.Headers {
+func (c *ChromeDP) HttpHeaders() network.Headers {
 	headers := c.httpHeaders
 	if len(headers) == 0 {
 		headers = network.Headers{
@@ -43,7 +47,7 @@ func (c ChromeDP) HttpHeaders() network.Headers {
 // Flags
 // headless: true
 // blink-settings: imagesEnabled=false
-func (c *ChromeDP) NewContext(timeout int, logger log.Logger) (context.Context, []context.CancelFunc) {
+func (c *ChromeDP) NewContext(timeout int, logger Logger) (context.Context, []context.CancelFunc) {
 	cancelFunctions := make([]context.CancelFunc, 0)
 	options := c.ExecAllocatorOptions
 	if len(options) == 0 {
@@ -56,7 +60,10 @@ func (c *ChromeDP) NewContext(timeout int, logger log.Logger) (context.Context,
 	cancelFunctions = append(cancelFunctions, cancel)
 
 	// also set up a custom logger
-	taskCtx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(logger.Printf))
+	taskCtx, cancel := chromedp.NewContext(allocCtx,
+		chromedp.WithLogf(logf(logger, LevelInfo)),
+		chromedp.WithErrorf(logf(logger, LevelError)),
+	)
 	cancelFunctions = append(cancelFunctions, cancel)
 
 	// create a timeout
@@ -71,7 +78,7 @@ func timeoutDuration(timeout int) time.Duration {
 	return duration
 }
 
-func (c ChromeDP) RunWithTimeOut(ctx *context.Context, timeout int, tasks chromedp.Tasks) chromedp.ActionFunc {
+func (c *ChromeDP) RunWithTimeOut(ctx *context.Context, timeout int, tasks chromedp.Tasks) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
 		timeoutContext, cancel := context.WithTimeout(ctx, timeoutDuration(timeout))
 		defer cancel()
@@ -79,7 +86,7 @@ func (c ChromeDP) RunWithTimeOut(ctx *context.Context, timeout int, tasks chrome
 	}
 }
 
-func (c ChromeDP) Click(sel interface{}, opts ...chromedp.QueryOption) chromedp.QueryAction {
+func (c *ChromeDP) Click(sel interface{}, opts ...chromedp.QueryOption) chromedp.QueryAction {
 	return chromedp.QueryAfter(sel, func(ctx context.Context, execCtx runtime.ExecutionContextID, nodes ...*cdp.Node) error {
 		if len(nodes) > 0 {
 			return chromedp.MouseClickNode(nodes[0]).Do(ctx)
@@ -1,8 +1,16 @@
 package pagereader
 
 type Config struct {
-	Timeout       int // timeout second, default 10 seconds
-	MaxTimeout    int
-	RetryTimes    int
-	MaxRetryTimes int
+	Timeout          int // timeout second, default 10 seconds
+	MaxTimeout       int
+	RetryTimes       int
+	MaxRetryTimes    int
+	Dialog           DialogHandling     // JavaScript dialogs handling
+	Popup            PopupPolicy        // New windows or tabs handling
+	ScreenshotOnOpen *ScreenshotOptions // Take screenshot after Open if not nil
+	Archive          *ArchiveOptions    // Write PDF/MHTML archives after Open if not nil
+	WARC             *WARCWriter        // Write WARC records for every Open if not nil
+	DebugDir         string             // Failure artifacts directory in Debug mode
+	TimingOnOpen     bool               // Collect navigation timing and web vitals after Open
+	AutoResync       bool               // Resync Doc from the live DOM after WaitReady, Refresh and Do
 }
@@ -0,0 +1,171 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrJavaScriptException = errors.New("pagereader: javascript exception")
+
+const ConsoleLevelException = "exception" // Level of uncaught exceptions
+
+// ConsoleMessage Browser console message or uncaught JavaScript exception
+type ConsoleMessage struct {
+	Level     string    `json:"level"` // log, debug, info, error, warning... or exception
+	Text      string    `json:"text"`
+	URL       string    `json:"url"`
+	Line      int64     `json:"line"`   // 0-based
+	Column    int64     `json:"column"` // 0-based
+	Stack     string    `json:"stack"`
+	Exception bool      `json:"exception"`
+	Time      time.Time `json:"time"`
+}
+
+func (m ConsoleMessage) String() string {
+	s := fmt.Sprintf("[%s] %s", m.Level, m.Text)
+	if m.URL != "" {
+		s += fmt.Sprintf(" (%s:%d:%d)", m.URL, m.Line+1, m.Column+1)
+	}
+	return s
+}
+
+type consoleCollector struct {
+	sync.Mutex
+	messages []ConsoleMessage
+}
+
+// listen Collect console messages until ctx canceled
+func (c *consoleCollector) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		var msg ConsoleMessage
+		switch e := ev.(type) {
+		case *runtime.EventConsoleAPICalled:
+			msg = ConsoleMessage{
+				Level: string(e.Type),
+				Text:  remoteObjectsText(e.Args),
+				Stack: stackTraceText(e.StackTrace),
+			}
+			if e.Timestamp != nil {
+				msg.Time = e.Timestamp.Time()
+			}
+			if e.StackTrace != nil && len(e.StackTrace.CallFrames) > 0 {
+				frame := e.StackTrace.CallFrames[0]
+				msg.URL = frame.URL
+				msg.Line = frame.LineNumber
+				msg.Column = frame.ColumnNumber
+			}
+		case *runtime.EventExceptionThrown:
+			if e.ExceptionDetails == nil {
+				return
+			}
+			details := e.ExceptionDetails
+			msg = ConsoleMessage{
+				Level:     ConsoleLevelException,
+				Text:      details.Text,
+				URL:       details.URL,
+				Line:      details.LineNumber,
+				Column:    details.ColumnNumber,
+				Stack:     stackTraceText(details.StackTrace),
+				Exception: true,
+			}
+			if details.Exception != nil && details.Exception.Description != "" {
+				msg.Text = details.Exception.Description
+			}
+			if e.Timestamp != nil {
+				msg.Time = e.Timestamp.Time()
+			}
+		default:
+			return
+		}
+		if msg.Time.IsZero() {
+			msg.Time = time.Now()
+		}
+		c.Lock()
+		c.messages = append(c.messages, msg)
+		c.Unlock()
+	})
+}
+
+func (c *consoleCollector) Messages() []ConsoleMessage {
+	c.Lock()
+	defer c.Unlock()
+	messages := make([]ConsoleMessage, len(c.messages))
+	copy(messages, c.messages)
+	return messages
+}
+
+func remoteObjectsText(objects []*runtime.RemoteObject) string {
+	values := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if object == nil {
+			continue
+		}
+		var value string
+		if len(object.Value) > 0 {
+			value = string(object.Value)
+			if s, err := strconv.Unquote(value); err == nil {
+				value = s
+			}
+		} else if object.Description != "" {
+			value = object.Description
+		} else if object.UnserializableValue != "" {
+			value = string(object.UnserializableValue)
+		} else {
+			value = string(object.Type)
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, " ")
+}
+
+func stackTraceText(stackTrace *runtime.StackTrace) string {
+	if stackTrace == nil {
+		return ""
+	}
+	sb := strings.Builder{}
+	for _, frame := range stackTrace.CallFrames {
+		name := frame.FunctionName
+		if name == "" {
+			name = "(anonymous)"
+		}
+		sb.WriteString(fmt.Sprintf("    at %s (%s:%d:%d)\n", name, frame.URL, frame.LineNumber+1, frame.ColumnNumber+1))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// SetFailOnException Open will return ErrJavaScriptException when an uncaught exception matches the pattern
+func (pr *PageReader) SetFailOnException(pattern *regexp.Regexp) *PageReader {
+	pr.failOnException = pattern
+	return pr
+}
+
+// Exceptions Uncaught JavaScript exceptions of the current page
+func (pr PageReader) Exceptions() []ConsoleMessage {
+	exceptions := make([]ConsoleMessage, 0)
+	for _, msg := range pr.ConsoleMessages {
+		if msg.Exception {
+			exceptions = append(exceptions, msg)
+		}
+	}
+	return exceptions
+}
+
+func (pr PageReader) matchException() error {
+	if pr.failOnException == nil {
+		return nil
+	}
+	for _, msg := range pr.Exceptions() {
+		if pr.failOnException.MatchString(msg.Text) {
+			return fmt.Errorf("%w: %s", ErrJavaScriptException, msg.Text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"regexp"
+	"testing"
+)
+
+func TestRemoteObjectsText(t *testing.T) {
+	objects := []*runtime.RemoteObject{
+		{Type: runtime.TypeString, Value: []byte(`"hello"`)},
+		{Type: runtime.TypeNumber, Value: []byte(`42`)},
+		{Type: runtime.TypeObject, Description: "Object"},
+	}
+	if text := remoteObjectsText(objects); text != "hello 42 Object" {
+		t.Errorf("remoteObjectsText = %q", text)
+	}
+}
+
+func TestPageReader_MatchException(t *testing.T) {
+	pr := PageReader{
+		ConsoleMessages: []ConsoleMessage{
+			{Level: "log", Text: "TypeError: ignore me"},
+			{Level: ConsoleLevelException, Text: "TypeError: a is undefined", Exception: true},
+		},
+	}
+	if err := pr.matchException(); err != nil {
+		t.Errorf("without pattern got error: %v", err)
+	}
+	pr.SetFailOnException(regexp.MustCompile(`^ReferenceError`))
+	if err := pr.matchException(); err != nil {
+		t.Errorf("unmatched pattern got error: %v", err)
+	}
+	pr.SetFailOnException(regexp.MustCompile(`^TypeError`))
+	if err := pr.matchException(); !errors.Is(err, ErrJavaScriptException) {
+		t.Errorf("expected ErrJavaScriptException, got %v", err)
+	}
+}
+
+func TestPageReader_OpenWithoutBrowser(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	if _, err := pr.Open(context.Background(), "https://example.com/", 10); !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
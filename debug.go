@@ -0,0 +1,148 @@
+package pagereader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/chromedp"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// selectorTrace Selectors tried by extraction helpers and extraction failures dumped on current page
+type selectorTrace struct {
+	sync.Mutex
+	selectors []string
+	dumped    map[string]bool
+}
+
+func (t *selectorTrace) add(format string, v ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	t.selectors = append(t.selectors, fmt.Sprintf(format, v...))
+	t.Unlock()
+}
+
+func (t *selectorTrace) reset() {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	t.selectors = nil
+	t.dumped = nil
+	t.Unlock()
+}
+
+// firstDump Whether key is not dumped on current page yet, it's marked as dumped
+func (t *selectorTrace) firstDump(key string) bool {
+	if t == nil {
+		return true
+	}
+	t.Lock()
+	defer t.Unlock()
+	if t.dumped[key] {
+		return false
+	}
+	if t.dumped == nil {
+		t.dumped = make(map[string]bool)
+	}
+	t.dumped[key] = true
+	return true
+}
+
+func (t *selectorTrace) Selectors() []string {
+	if t == nil {
+		return nil
+	}
+	t.Lock()
+	defer t.Unlock()
+	selectors := make([]string, len(t.selectors))
+	copy(selectors, t.selectors)
+	return selectors
+}
+
+// SetDebugDir Failure artifacts will be written into dir in Debug mode
+func (pr *PageReader) SetDebugDir(dir string) *PageReader {
+	pr.Config.DebugDir = dir
+	return pr
+}
+
+// DumpArtifacts Write artifact bundle of current page into Config.DebugDir, return bundle directory.
+// Bundle files: page.html, screenshot.png, console.log, network.json, notify.txt, selectors.txt
+// ctx is used to read live HTML and screenshot if it's a chromedp context.
+func (pr PageReader) DumpArtifacts(ctx context.Context, notify *Notify) (dir string, err error) {
+	if pr.Config.DebugDir == "" {
+		return "", nil
+	}
+	name := "unknown"
+	if notify != nil {
+		name = notify.FunctionName
+	}
+	dir = filepath.Join(pr.Config.DebugDir, fmt.Sprintf("%s_%s_%s", time.Now().Format("20060102150405.000"), name, ArchiveName(pr.URL)))
+
+	html := pr.html
+	screenshot := []byte(nil)
+	if pr.Image != nil && pr.Image.Format == ScreenshotPNG {
+		screenshot = pr.Image.Data
+	}
+	if chromedp.FromContext(ctx) != nil && ctx.Err() == nil {
+		var liveHtml string
+		var buf []byte
+		if e := chromedp.Run(ctx, pr.ChromeDP.RunWithTimeOut(&ctx, 5, chromedp.Tasks{
+			chromedp.OuterHTML("html", &liveHtml, chromedp.ByQuery),
+			screenshotTask(ScreenshotOptions{Format: ScreenshotPNG}, &buf),
+		})); e == nil {
+			html = liveHtml
+			screenshot = buf
+		} else {
+			pr.Logger.Warn("Read live page failed", F("error", e))
+		}
+	}
+
+	files := map[string][]byte{
+		"page.html":     []byte(html),
+		"selectors.txt": []byte(strings.Join(pr.trace.Selectors(), "\n")),
+	}
+	if len(screenshot) > 0 {
+		files["screenshot.png"] = screenshot
+	}
+	if notify != nil {
+		files["notify.txt"] = []byte(notify.String())
+	}
+	console := strings.Builder{}
+	for _, msg := range pr.ConsoleMessages {
+		console.WriteString(msg.Time.Format("2006-01-02 15:04:05") + " " + msg.String() + "\n")
+		if msg.Stack != "" {
+			console.WriteString(msg.Stack + "\n")
+		}
+	}
+	files["console.log"] = []byte(console.String())
+	if b, e := json.MarshalIndent(map[string]interface{}{
+		"document":  pr.Document,
+		"resources": pr.Resources,
+	}, "", "  "); e == nil {
+		files["network.json"] = b
+	}
+
+	for filename, data := range files {
+		if err = writeFile(filepath.Join(dir, filename), data); err != nil {
+			return
+		}
+	}
+	pr.Logger.Info("Artifacts dumped", F("dir", dir))
+	return
+}
+
+// dumpOnFailure Dump artifacts only in Debug mode
+func (pr PageReader) dumpOnFailure(ctx context.Context, notify *Notify) {
+	if !pr.Debug || pr.Config.DebugDir == "" {
+		return
+	}
+	if _, err := pr.DumpArtifacts(ctx, notify); err != nil {
+		pr.Logger.Error("Dump artifacts failed", F("error", err))
+	}
+}
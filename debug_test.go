@@ -0,0 +1,89 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPageReader_DumpOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	pr := NewPageReader(10, NopLogger())
+	pr.SetDebugDir(dir)
+	pr.URL = "https://example.com/"
+	pr.SetHtml(`<html><body><h1 id="title">Hello</h1></body></html>`)
+
+	pr.Text("#missing")
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*")); len(matches) != 0 {
+		t.Fatalf("artifacts should only be dumped in Debug mode")
+	}
+
+	pr.Debug = true
+	if pr.Text("#title") != "Hello" {
+		t.Fatalf("unexpected text")
+	}
+	pr.Text("#missing", "#missing2")
+	matches, _ := filepath.Glob(filepath.Join(dir, "*_Text_*"))
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 artifact bundle, got %d", len(matches))
+	}
+	for _, name := range []string{"page.html", "console.log", "network.json", "notify.txt", "selectors.txt"} {
+		if _, err := os.Stat(filepath.Join(matches[0], name)); err != nil {
+			t.Errorf("%s not found", name)
+		}
+	}
+	b, _ := os.ReadFile(filepath.Join(matches[0], "selectors.txt"))
+	if !strings.Contains(string(b), "Text: #missing2") {
+		t.Errorf("unexpected selectors: %s", b)
+	}
+	// Same failure is dumped once per page, optional fields, existence checks and tables are not dumped
+	pr.Text("#missing", "#missing2")
+	if pr.Exists("#name") || pr.Count("#name") != 0 {
+		t.Fatal("#name should not exist")
+	}
+	pr.Table("#name")
+	pr.KeyValues("#name")
+	var optional struct {
+		Name string `pr:"#name"`
+	}
+	_ = pr.Unmarshal(&optional)
+	_ = pr.Extract(Schema{"name": {Selector: Selectors{"#name"}}})
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*")); len(matches) != 1 {
+		t.Errorf("expected 1 artifact bundle, got %d", len(matches))
+	}
+	var required struct {
+		Name string `pr:"#name;required"`
+	}
+	if err := pr.Unmarshal(&required); err == nil {
+		t.Fatal("required field should fail")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*_Unmarshal_*")); len(matches) != 1 {
+		t.Errorf("missing required field should be dumped, got %d bundles", len(matches))
+	}
+	if _, err := pr.DumpArtifacts(context.Background(), nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPageReader_WaitReadyDump(t *testing.T) {
+	dir := t.TempDir()
+	pr := NewPageReader(10, NopLogger())
+	pr.SetDebugDir(dir).Debug = true
+	pr.URL = "https://example.com/"
+	if pr.WaitReady(noBrowserContext(t), "#late"); !errors.Is(pr.Error, errNoBrowser) {
+		t.Fatalf("unexpected error: %v", pr.Error)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*_WaitReady_*", "notify.txt"))
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 artifact bundle, got %d", len(matches))
+	}
+	b, _ := os.ReadFile(matches[0])
+	for _, s := range []string{"Mark Name: #late", "Success: false", errNoBrowser.Error(), "RunTasks WaitReady", "WaitReady failed"} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("dumped notify should contain %q:\n%s", s, b)
+		}
+	}
+}
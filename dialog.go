@@ -0,0 +1,202 @@
+package pagereader
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"sync"
+	"time"
+)
+
+type DialogAction string
+
+const (
+	DialogNone    DialogAction = ""        // Leave the dialog opened, page will be blocked until timeout
+	DialogAccept  DialogAction = "accept"  // Accept dialog, prompt dialog will be answered with PromptText
+	DialogDismiss DialogAction = "dismiss" // Dismiss dialog
+)
+
+// DialogHandling JavaScript dialog (alert, confirm, prompt, beforeunload) handling config
+type DialogHandling struct {
+	Action     DialogAction
+	PromptText string
+}
+
+// Dialog JavaScript dialog opened during Open
+type Dialog struct {
+	Type          string       `json:"type"` // alert, confirm, prompt or beforeunload
+	Message       string       `json:"message"`
+	URL           string       `json:"url"`
+	DefaultPrompt string       `json:"defaultPrompt"`
+	Action        DialogAction `json:"action"`
+	Time          time.Time    `json:"time"`
+}
+
+type PopupPolicy string
+
+const (
+	PopupAllow   PopupPolicy = ""        // Do nothing, popups keep opened
+	PopupBlock   PopupPolicy = "block"   // Close popups immediately
+	PopupFollow  PopupPolicy = "follow"  // Open URL of the first popup in current tab, up to maxPopupFollows times
+	PopupCapture PopupPolicy = "capture" // Read popups as separate page results, see PageReader.Popups
+)
+
+// maxPopupFollows Max popups followed by one Open, so pages open each other can't recurse without bound
+const maxPopupFollows = 5
+
+// popupFollowsKey Context key of followed popups count
+type popupFollowsKey struct{}
+
+func popupFollows(ctx context.Context) int {
+	n, _ := ctx.Value(popupFollowsKey{}).(int)
+	return n
+}
+
+// SetDialogHandling Set JavaScript dialogs handling, prompt dialogs will be answered with promptText when accept
+func (pr *PageReader) SetDialogHandling(action DialogAction, promptText string) *PageReader {
+	pr.Config.Dialog = DialogHandling{Action: action, PromptText: promptText}
+	return pr
+}
+
+// SetPopupPolicy Set how to deal with new windows or tabs opened by the page
+func (pr *PageReader) SetPopupPolicy(policy PopupPolicy) *PageReader {
+	pr.Config.Popup = policy
+	return pr
+}
+
+type dialogCollector struct {
+	sync.Mutex
+	handling DialogHandling
+	popup    PopupPolicy
+	dialogs  []Dialog
+	popups   []target.ID
+}
+
+// listen Handle dialogs and popups until ctx canceled
+func (c *dialogCollector) listen(ctx context.Context) {
+	if c.handling.Action != DialogNone {
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			e, ok := ev.(*page.EventJavascriptDialogOpening)
+			if !ok {
+				return
+			}
+			// Listener must not block, so handle it in another goroutine
+			go func(action chromedp.Action) {
+				_ = chromedp.Run(ctx, action)
+			}(c.onDialog(e))
+		})
+	}
+
+	if c.popup != PopupAllow {
+		chromedp.ListenBrowser(ctx, func(ev interface{}) {
+			e, ok := ev.(*target.EventTargetCreated)
+			if !ok {
+				return
+			}
+			cc := chromedp.FromContext(ctx)
+			if cc.Target == nil || !c.onTargetCreated(cc.Target.TargetID, e) {
+				return
+			}
+			go func(id target.ID) {
+				_ = target.CloseTarget(id).Do(cdp.WithExecutor(ctx, cc.Browser))
+			}(e.TargetInfo.TargetID)
+		})
+	}
+}
+
+// onDialog Record dialog and return the action to handle it
+func (c *dialogCollector) onDialog(e *page.EventJavascriptDialogOpening) chromedp.Action {
+	c.Lock()
+	c.dialogs = append(c.dialogs, Dialog{
+		Type:          string(e.Type),
+		Message:       e.Message,
+		URL:           e.URL,
+		DefaultPrompt: e.DefaultPrompt,
+		Action:        c.handling.Action,
+		Time:          time.Now(),
+	})
+	c.Unlock()
+	params := page.HandleJavaScriptDialog(c.handling.Action == DialogAccept)
+	if e.Type == page.DialogTypePrompt && c.handling.Action == DialogAccept {
+		params = params.WithPromptText(c.handling.PromptText)
+	}
+	return params
+}
+
+// onTargetCreated Record popup opened by opener, return true if it should be closed
+func (c *dialogCollector) onTargetCreated(opener target.ID, e *target.EventTargetCreated) (block bool) {
+	if e.TargetInfo == nil || e.TargetInfo.Type != "page" || e.TargetInfo.OpenerID == "" || e.TargetInfo.OpenerID != opener {
+		return false
+	}
+	c.Lock()
+	c.popups = append(c.popups, e.TargetInfo.TargetID)
+	c.Unlock()
+	return c.popup == PopupBlock
+}
+
+func (c *dialogCollector) Dialogs() []Dialog {
+	c.Lock()
+	defer c.Unlock()
+	dialogs := make([]Dialog, len(c.dialogs))
+	copy(dialogs, c.dialogs)
+	return dialogs
+}
+
+func (c *dialogCollector) Popups() []target.ID {
+	c.Lock()
+	defer c.Unlock()
+	popups := make([]target.ID, len(c.popups))
+	copy(popups, c.popups)
+	return popups
+}
+
+// readPopup Read popup target as a separate page result, cancel the attached context will close the popup.
+// Popup shares trace, Tracer and Metrics of the reader, so it's in the same timeline and Debug artifacts.
+func (pr *PageReader) readPopup(ctx context.Context, id target.ID, timeout int) (popup *PageReader, err error) {
+	ctx, span := pr.startSpan(ctx, "ReadPopup", F("target", string(id)))
+	metrics := pr.metrics()
+	metrics.AddActiveTabs(1)
+	defer metrics.AddActiveTabs(-1)
+	popupCtx, cancel := chromedp.NewContext(ctx, chromedp.WithTargetID(id))
+	defer cancel()
+
+	popup = &PageReader{
+		Debug:       pr.Debug,
+		Config:      pr.Config,
+		Logger:      pr.Logger,
+		ChromeDP:    pr.ChromeDP,
+		trace:       pr.trace,
+		Tracer:      pr.Tracer,
+		Metrics:     pr.Metrics,
+		spanContext: pr.spanContextOf(ctx),
+	}
+	var url, title, html string
+	defer func() {
+		span.SetAttributes(F("url", url), F("bytes", len(html)))
+		span.End(err)
+	}()
+	err = chromedp.Run(popupCtx, pr.ChromeDP.RunWithTimeOut(&popupCtx, timeout, chromedp.Tasks{
+		chromedp.WaitReady("body", chromedp.ByQuery),
+		chromedp.Location(&url),
+		chromedp.Title(&title),
+		chromedp.OuterHTML("html", &html, chromedp.ByQuery),
+	}))
+	popup.URL = url
+	popup.Title = title
+	popup.Error = err
+	popup.SetHtml(html)
+	return popup, err
+}
+
+// popupURL Current URL of popup target, only the location is read, so following a popup doesn't wait for its document
+func (pr *PageReader) popupURL(ctx context.Context, id target.ID, timeout int) (url string, err error) {
+	metrics := pr.metrics()
+	metrics.AddActiveTabs(1)
+	defer metrics.AddActiveTabs(-1)
+	popupCtx, cancel := chromedp.NewContext(ctx, chromedp.WithTargetID(id))
+	defer cancel()
+	err = chromedp.Run(popupCtx, pr.ChromeDP.RunWithTimeOut(&popupCtx, timeout, chromedp.Tasks{chromedp.Location(&url)}))
+	return
+}
@@ -0,0 +1,103 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+	"testing"
+)
+
+func TestDialogCollector_OnDialog(t *testing.T) {
+	tests := []struct {
+		action     DialogAction
+		dialogType page.DialogType
+		accept     bool
+		promptText string
+	}{
+		{DialogAccept, page.DialogTypeAlert, true, ""},
+		{DialogAccept, page.DialogTypePrompt, true, "yes"},
+		{DialogDismiss, page.DialogTypeConfirm, false, ""},
+		{DialogDismiss, page.DialogTypePrompt, false, ""},
+	}
+	for _, test := range tests {
+		c := &dialogCollector{handling: DialogHandling{Action: test.action, PromptText: "yes"}}
+		action := c.onDialog(&page.EventJavascriptDialogOpening{Type: test.dialogType, Message: "Leave?", URL: "https://example.com/"})
+		params, ok := action.(*page.HandleJavaScriptDialogParams)
+		if !ok || params.Accept != test.accept || params.PromptText != test.promptText {
+			t.Errorf("%s %s: unexpected params %+v", test.action, test.dialogType, action)
+		}
+		dialogs := c.Dialogs()
+		if len(dialogs) != 1 || dialogs[0].Type != string(test.dialogType) || dialogs[0].Message != "Leave?" || dialogs[0].Action != test.action {
+			t.Errorf("%s %s: unexpected dialogs %+v", test.action, test.dialogType, dialogs)
+		}
+	}
+}
+
+func TestDialogCollector_OnTargetCreated(t *testing.T) {
+	created := func(id, opener target.ID, typ string) *target.EventTargetCreated {
+		return &target.EventTargetCreated{TargetInfo: &target.Info{TargetID: id, OpenerID: opener, Type: typ}}
+	}
+	c := &dialogCollector{popup: PopupBlock}
+	if !c.onTargetCreated("main", created("p1", "main", "page")) {
+		t.Error("popup of current tab should be blocked")
+	}
+	if c.onTargetCreated("main", created("p2", "other", "page")) || c.onTargetCreated("main", created("w1", "main", "service_worker")) ||
+		c.onTargetCreated("main", created("p3", "", "page")) {
+		t.Error("targets not opened by current tab should be ignored")
+	}
+	if popups := c.Popups(); len(popups) != 1 || popups[0] != "p1" {
+		t.Errorf("unexpected popups: %v", popups)
+	}
+
+	c = &dialogCollector{popup: PopupCapture}
+	if c.onTargetCreated("main", created("p1", "main", "page")) || len(c.Popups()) != 1 {
+		t.Error("captured popup should be recorded and kept opened")
+	}
+}
+
+// recordTracer Record names of started spans
+type recordTracer struct {
+	names []string
+}
+
+func (t *recordTracer) Start(ctx context.Context, name string, attrs ...Field) (context.Context, Span) {
+	t.names = append(t.names, name)
+	return ctx, noopSpan{}
+}
+
+func TestPageReader_ReadPopup(t *testing.T) {
+	tracer := &recordTracer{}
+	metrics := &countMetrics{}
+	pr := NewPageReader(10, NopLogger())
+	pr.SetTracer(tracer).SetMetrics(metrics)
+	popup, err := pr.readPopup(noBrowserContext(t), "p1", 5)
+	if !errors.Is(err, errNoBrowser) || popup == nil {
+		t.Fatalf("unexpected popup: %+v, error: %v", popup, err)
+	}
+	if metrics.tabs != 0 || metrics.maxTabs != 1 {
+		t.Errorf("popup tab should be counted while it's read: %+v", metrics)
+	}
+	if popup.Tracer != pr.Tracer || popup.Metrics != pr.Metrics || popup.trace != pr.trace {
+		t.Errorf("popup should share trace, Tracer and Metrics of the reader")
+	}
+	popup.Text("#missing")
+	if selectors := pr.trace.Selectors(); len(selectors) != 1 || selectors[0] != "Text: #missing" {
+		t.Errorf("popup queries should be in the reader's trace: %v", selectors)
+	}
+	if len(tracer.names) != 2 || tracer.names[0] != "ReadPopup" || tracer.names[1] != "Text" {
+		t.Errorf("unexpected spans: %v", tracer.names)
+	}
+}
+
+func TestPageReader_PopupURL(t *testing.T) {
+	metrics := &countMetrics{}
+	pr := NewPageReader(10, NopLogger())
+	pr.SetMetrics(metrics)
+	if url, err := pr.popupURL(noBrowserContext(t), "p1", 5); !errors.Is(err, errNoBrowser) || url != "" {
+		t.Fatalf("unexpected popup URL: %s, error: %v", url, err)
+	}
+	if metrics.tabs != 0 || metrics.maxTabs != 1 {
+		t.Errorf("popup tab should be counted while its URL is read: %+v", metrics)
+	}
+}
@@ -0,0 +1,274 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"github.com/PuerkitoBio/goquery"
+	"strings"
+	"time"
+)
+
+// Match Result of a selector fallback chain
+type Match struct {
+	Selector string   // Selector in the chain which matched, empty if nothing matched
+	Index    int      // Index of the matched selector in the chain, -1 if nothing matched
+	Count    int      // Number of elements matched by the selector
+	Values   []string // Extracted values
+}
+
+func (m Match) Matched() bool {
+	return m.Index >= 0
+}
+
+// Fallback Whether matched by a fallback selector rather than the primary one
+func (m Match) Fallback() bool {
+	return m.Index > 0
+}
+
+// Value First extracted value
+func (m Match) Value() string {
+	if len(m.Values) == 0 {
+		return ""
+	}
+	return m.Values[0]
+}
+
+// extractFunc Extract values from selection, return false if the selection should be treated as not matched
+type extractFunc func(s *goquery.Selection) (values []string, matched bool)
+
+// lookup Try selectors in order until one matched, every extraction helper goes through it
+func (pr PageReader) lookup(name string, selectors []string, extract extractFunc, attrs ...Field) Match {
+	m := pr.lookupIn(pr.docRoot(), name, selectors, extract, attrs...)
+	if !m.Matched() {
+		pr.extractionFailed(context.Background(), name, strings.Join(selectors, ", "), errors.New("no selector matched"))
+	}
+	return m
+}
+
+// docRoot Selection of the whole document, nil if there is no document
+func (pr PageReader) docRoot() *goquery.Selection {
+	if pr.Doc == nil {
+		return nil
+	}
+	return pr.Doc.Selection
+}
+
+// lookupIn Same as lookup but selectors are scoped in root, artifacts are not dumped if nothing matched,
+// because fields of schema and Unmarshal are optional unless required
+func (pr PageReader) lookupIn(root *goquery.Selection, name string, selectors []string, extract extractFunc, attrs ...Field) Match {
+	m := Match{Index: -1}
+	finish := pr.startExtraction(pr.extractionContext(), name, append(attrs, F("selectors", strings.Join(selectors, ", ")))...)
+	for i, sel := range selectors {
+		pr.trace.add("%s: %s", name, sel)
+		var values []string
+		matched := false
+		s := findIn(root, sel)
+		if s != nil && s.Length() > 0 {
+			values, matched = extract(s)
+		}
+		pr.debug("Query "+name, append(attrs, F("selector", sel), F("matched", matched), F("values", values))...)
+		if matched {
+			m = Match{Selector: sel, Index: i, Count: s.Length(), Values: values}
+			break
+		}
+	}
+	if m.Fallback() {
+		pr.Logger.Warn("Fallback selector matched", F("function", name), F("primary", selectors[0]), F("selector", m.Selector))
+	}
+	finish(m.Matched(), len(strings.Join(m.Values, "")), nil)
+	return m
+}
+
+// startExtraction Start span and timer of an extraction helper, call the returned func when finished
+func (pr PageReader) startExtraction(ctx context.Context, name string, attrs ...Field) func(matched bool, bytes int, err error) {
+	_, span := pr.startSpan(ctx, name, attrs...)
+	start := time.Now()
+	return func(matched bool, bytes int, err error) {
+		pr.metrics().ObserveExtraction(strings.ToLower(name), time.Since(start))
+		span.SetAttributes(F("matched", matched), F("bytes", bytes))
+		span.End(err)
+	}
+}
+
+// extractionFailed Dump artifacts in Debug mode, ctx is used to capture live page if it's a chromedp context.
+// Every failed function and selectors are dumped once per page.
+func (pr PageReader) extractionFailed(ctx context.Context, name, mark string, err error) {
+	if !pr.Debug || pr.Config.DebugDir == "" || !pr.trace.firstDump(name+": "+mark) {
+		return
+	}
+	notify := NewNotify(name, mark)
+	notify.Error = err
+	pr.dumpOnFailure(ctx, notify)
+}
+
+// textOf Trimmed text of all elements joined, same as the live query, schema single text fields pass the first element only
+func textOf(s *goquery.Selection) ([]string, bool) {
+	value := strings.TrimSpace(s.Text())
+	return []string{value}, value != ""
+}
+
+func attrOf(attrName string) extractFunc {
+	return func(s *goquery.Selection) ([]string, bool) {
+		value, exists := s.Attr(attrName)
+		if !exists {
+			return nil, false
+		}
+		return []string{strings.TrimSpace(value)}, true
+	}
+}
+
+// MatchText Trimmed text of all elements matched by the first selector which has non-empty text
+func (pr PageReader) MatchText(selectors ...string) Match {
+	return pr.lookup("Text", selectors, textOf)
+}
+
+// MatchAttr Trimmed attribute of the first element matched by the first selector which has the attribute
+func (pr PageReader) MatchAttr(attrName string, selectors ...string) Match {
+	return pr.lookup("Attr", selectors, attrOf(attrName), F("attr", attrName))
+}
+
+// MatchHtml Inner HTML of the first element matched by the first selector which matches any element
+func (pr PageReader) MatchHtml(selectors ...string) Match {
+	return pr.lookup("Html", selectors, func(s *goquery.Selection) ([]string, bool) {
+		html, err := s.First().Html()
+		return []string{strings.TrimSpace(html)}, err == nil
+	})
+}
+
+// MatchOuterHtml Outer HTML of the first element matched by the first selector which matches any element
+func (pr PageReader) MatchOuterHtml(selectors ...string) Match {
+	return pr.lookup("OuterHtml", selectors, func(s *goquery.Selection) ([]string, bool) {
+		html, err := goquery.OuterHtml(s.First())
+		return []string{strings.TrimSpace(html)}, err == nil
+	})
+}
+
+// MatchTextAll Trimmed text of every element matched by the first selector which matches any element
+func (pr PageReader) MatchTextAll(selectors ...string) Match {
+	return pr.lookup("TextAll", selectors, func(s *goquery.Selection) ([]string, bool) {
+		values := s.Map(func(i int, s *goquery.Selection) string {
+			return strings.TrimSpace(s.Text())
+		})
+		return values, true
+	})
+}
+
+// MatchAttrAll Trimmed attribute values of every element matched by the first selector which has the attribute,
+// elements without the attribute are skipped
+func (pr PageReader) MatchAttrAll(attrName string, selectors ...string) Match {
+	return pr.lookup("AttrAll", selectors, func(s *goquery.Selection) ([]string, bool) {
+		values := make([]string, 0)
+		s.Each(func(i int, s *goquery.Selection) {
+			if value, exists := s.Attr(attrName); exists {
+				values = append(values, strings.TrimSpace(value))
+			}
+		})
+		return values, len(values) > 0
+	}, F("attr", attrName))
+}
+
+// InnerHtml Inner HTML of the first element matched by the first selector which matches any element
+func (pr PageReader) InnerHtml(selector string, selectors ...string) string {
+	return pr.MatchHtml(append([]string{selector}, selectors...)...).Value()
+}
+
+// OuterHtml Outer HTML of the first element matched by the first selector which matches any element
+func (pr PageReader) OuterHtml(selector string, selectors ...string) string {
+	return pr.MatchOuterHtml(append([]string{selector}, selectors...)...).Value()
+}
+
+// MatchCount Number of elements matched by the first selector which matches any element, in Match.Count.
+// Zero count is a valid answer, so artifacts are not dumped if nothing matched, same as LiveCount.
+func (pr PageReader) MatchCount(selectors ...string) Match {
+	return pr.lookupIn(pr.docRoot(), "Count", selectors, func(s *goquery.Selection) ([]string, bool) {
+		return nil, true
+	})
+}
+
+// MatchExists Same as MatchCount, for checking whether any selector matches an element
+func (pr PageReader) MatchExists(selectors ...string) Match {
+	return pr.lookupIn(pr.docRoot(), "Exists", selectors, func(s *goquery.Selection) ([]string, bool) {
+		return nil, true
+	})
+}
+
+// Count Number of elements matched by the first selector which matches any element
+func (pr PageReader) Count(selector string, selectors ...string) int {
+	return pr.MatchCount(append([]string{selector}, selectors...)...).Count
+}
+
+// Exists Whether any selector matches an element
+func (pr PageReader) Exists(selector string, selectors ...string) bool {
+	return pr.MatchExists(append([]string{selector}, selectors...)...).Matched()
+}
+
+// TextAll Trimmed text of every element matched by selector
+func (pr PageReader) TextAll(selector string, selectors ...string) []string {
+	m := pr.MatchTextAll(append([]string{selector}, selectors...)...)
+	if m.Values == nil {
+		return make([]string, 0)
+	}
+	return m.Values
+}
+
+// AttrAll Trimmed attribute values of every element matched by selector, elements without the attribute are skipped
+func (pr PageReader) AttrAll(selector, attrName string, selectors ...string) []string {
+	m := pr.MatchAttrAll(attrName, append([]string{selector}, selectors...)...)
+	if m.Values == nil {
+		return make([]string, 0)
+	}
+	return m.Values
+}
+
+// Each Call fn for every element matched by the first selector which matches any element
+func (pr *PageReader) Each(selector string, fn func(e *Element), selectors ...string) *PageReader {
+	var elements *goquery.Selection
+	pr.lookup("Each", append([]string{selector}, selectors...), func(s *goquery.Selection) ([]string, bool) {
+		elements = s
+		return nil, true
+	})
+	if elements != nil {
+		elements.Each(func(i int, s *goquery.Selection) {
+			fn(&Element{Index: i, Selection: s, pr: pr})
+		})
+	}
+	return pr
+}
+
+// Element A matched element, Text and Attr are scoped in it
+type Element struct {
+	Index     int
+	Selection *goquery.Selection
+	pr        *PageReader
+}
+
+// Text Trimmed text of the first non-empty selector inside the element, text of the element itself if no selectors
+func (e Element) Text(selectors ...string) (value string) {
+	if len(selectors) == 0 {
+		return strings.TrimSpace(e.Selection.Text())
+	}
+	for _, sel := range selectors {
+		value = strings.TrimSpace(findIn(e.Selection, sel).Text())
+		e.pr.debug("Query element text", F("index", e.Index), F("selector", sel), F("value", value))
+		if value != "" {
+			break
+		}
+	}
+	return
+}
+
+// Attr Trimmed attribute of the first element matched by selector inside the element, empty selector means the element itself,
+// fallback selectors are tried in order until one has the attribute
+func (e Element) Attr(selector, attrName string, selectors ...string) (value string, exists bool) {
+	for _, sel := range append([]string{selector}, selectors...) {
+		value, exists = findIn(e.Selection, sel).Attr(attrName)
+		if exists && value != "" {
+			value = strings.TrimSpace(value)
+		}
+		e.pr.debug("Query element attr", F("index", e.Index), F("selector", sel), F("attr", attrName), F("exists", exists), F("value", value))
+		if exists {
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package pagereader
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const searchResultHtml = `<html><body>
+<div class="result"><h2> First </h2><a href=" /1 ">Link 1</a><span class="price">$1</span></div>
+<div class="result"><h2>Second</h2><a href="/2">Link 2</a></div>
+<div class="result"><h2>Third</h2><a>Link 3</a><span class="sale">$3</span></div>
+</body></html>`
+
+func TestPageReader_TextAll(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(searchResultHtml)
+	if values := pr.TextAll(".result h2"); !reflect.DeepEqual(values, []string{"First", "Second", "Third"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if values := pr.TextAll(".missing"); len(values) != 0 {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestPageReader_AttrAll(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(searchResultHtml)
+	if values := pr.AttrAll(".result a", "href"); !reflect.DeepEqual(values, []string{"/1", "/2"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestPageReader_Each(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.Debug = true
+	pr.SetHtml(searchResultHtml)
+	prices := make([]string, 0)
+	links := make([]string, 0)
+	pr.Each(".result", func(e *Element) {
+		prices = append(prices, e.Text(".price", ".sale"))
+		href, _ := e.Attr("a", "href")
+		links = append(links, href)
+	})
+	if !reflect.DeepEqual(prices, []string{"$1", "", "$3"}) {
+		t.Errorf("unexpected prices: %v", prices)
+	}
+	if !reflect.DeepEqual(links, []string{"/1", "/2", ""}) {
+		t.Errorf("unexpected links: %v", links)
+	}
+}
+
+func TestPageReader_Match(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(searchResultHtml)
+	m := pr.MatchText("#title", ".result h2")
+	if !m.Matched() || !m.Fallback() || m.Selector != ".result h2" || m.Index != 1 || m.Count != 3 || m.Value() != "First SecondThird" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m = pr.MatchAttr("href", ".result .price", ".result a"); m.Selector != ".result a" || m.Value() != "/1" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m = pr.MatchAttrAll("href", ".missing", ".result a"); m.Index != 1 || !reflect.DeepEqual(m.Values, []string{"/1", "/2"}) {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m = pr.MatchHtml(".missing", ".result"); m.Value() != `<h2> First </h2><a href=" /1 ">Link 1</a><span class="price">$1</span>` {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m = pr.MatchCount(".missing", ".result a"); m.Selector != ".result a" || m.Index != 1 || m.Count != 3 {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m = pr.MatchExists(".result", ".missing"); !m.Matched() || m.Fallback() || m.Count != 3 {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if m = pr.MatchText(".missing", "#missing"); m.Matched() || m.Index != -1 || m.Selector != "" || m.Value() != "" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+}
+
+func TestPageReader_Fallback(t *testing.T) {
+	var b bytes.Buffer
+	// Drift of primary selectors must be visible with the default level
+	pr := NewPageReader(10, NewStdLogger(log.New(&b, "", 0), LevelInfo))
+	pr.SetHtml(searchResultHtml)
+	if href, exists := pr.Attr(".price", "href", ".result a"); !exists || href != "/1" {
+		t.Errorf("unexpected attr: %s, %v", href, exists)
+	}
+	if values := pr.TextAll(".missing", ".sale"); !reflect.DeepEqual(values, []string{"$3"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if n := pr.Count(".missing", ".result"); n != 3 {
+		t.Errorf("unexpected count: %d", n)
+	}
+	if pr.Exists(".missing", "#missing") {
+		t.Error("expected not exists")
+	}
+	if html := pr.OuterHtml(".missing", ".sale"); html != `<span class="sale">$3</span>` {
+		t.Errorf("unexpected html: %s", html)
+	}
+	n := 0
+	pr.Each(".missing", func(e *Element) {
+		n++
+	}, ".result")
+	if n != 3 {
+		t.Errorf("unexpected each count: %d", n)
+	}
+	if !strings.Contains(b.String(), "[WARN] Fallback selector matched function=Attr primary=.price selector=\".result a\"") {
+		t.Errorf("fallback should be logged at Warn level: %s", b.String())
+	}
+}
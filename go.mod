@@ -4,8 +4,13 @@ go 1.17
 
 require (
 	github.com/PuerkitoBio/goquery v1.8.0
+	github.com/antchfx/htmlquery v1.2.5
+	github.com/antchfx/xpath v1.2.1
 	github.com/chromedp/cdproto v0.0.0-20220124012806-175728ec2004
 	github.com/chromedp/chromedp v0.7.6
+	github.com/mailru/easyjson v0.7.7
+	golang.org/x/net v0.0.0-20210916014120-12bc252f5db8
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,8 +19,8 @@ require (
 	github.com/gobwas/httphead v0.1.0 // indirect
 	github.com/gobwas/pool v0.2.1 // indirect
 	github.com/gobwas/ws v1.1.0 // indirect
+	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
 	github.com/josharian/intern v1.0.0 // indirect
-	github.com/mailru/easyjson v0.7.7 // indirect
-	golang.org/x/net v0.0.0-20210916014120-12bc252f5db8 // indirect
 	golang.org/x/sys v0.0.0-20211124211545-fe61309f8881 // indirect
+	golang.org/x/text v0.3.6 // indirect
 )
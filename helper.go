@@ -0,0 +1,16 @@
+package pagereader
+
+import (
+	_ "embed"
+)
+
+const (
+	HelperScriptName = "helper.js"
+	HelperObjectName = "__pageReader" // window.__pageReader
+)
+
+// HelperScript In-page helper API, it's injected once per document.
+// Functions: query, first, text, attr, scroll, click, sleep, wait
+//
+//go:embed scripts/helper.js
+var HelperScript string
@@ -0,0 +1,75 @@
+package pagereader
+
+import (
+	"errors"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"net/url"
+	"os/exec"
+	"testing"
+)
+
+const helperHtml = `<html><body>
+<p id="a"> Hello </p>
+<a id="link" href=" /x ">x</a>
+<div class="item">1</div><div class="item">2</div>
+</body></html>`
+
+func TestHelperScript(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	scripts := pr.ChromeDP.Scripts()
+	if len(scripts) == 0 || scripts[0].Name != HelperScriptName {
+		t.Fatalf("helper script should be registered by default: %+v", scripts)
+	}
+
+	ctx, cancelFunctions := pr.ChromeDP.NewContext(20, NopLogger())
+	defer func() {
+		for i := len(cancelFunctions) - 1; i >= 0; i-- {
+			cancelFunctions[i]()
+		}
+	}()
+	var result struct {
+		Text    string      `json:"text"`
+		Attr    string      `json:"attr"`
+		Missing interface{} `json:"missing"`
+		Count   int         `json:"count"`
+		Frozen  bool        `json:"frozen"`
+		Waited  bool        `json:"waited"`
+		Timeout bool        `json:"timeout"`
+	}
+	err := chromedp.Run(ctx,
+		pr.ChromeDP.InjectScripts(),
+		chromedp.Navigate("data:text/html,"+url.PathEscape(helperHtml)),
+		chromedp.Evaluate(`(async function () {
+    const h = window.`+HelperObjectName+`;
+    setTimeout(function () {
+        const el = document.createElement("span");
+        el.id = "late";
+        document.body.appendChild(el);
+    }, 50);
+    return {
+        text: h.text("#a"),
+        attr: h.attr("#link", "href"),
+        missing: h.attr("#link", "title"),
+        count: h.query(".item").length,
+        frozen: Object.isFrozen(h),
+        waited: await h.wait("#late", 2000),
+        timeout: await h.wait("#never", 100)
+    };
+})()`, &result, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}),
+	)
+	if errors.Is(err, exec.ErrNotFound) {
+		t.Skip("chrome is not available")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Text != "Hello" || result.Attr != "/x" || result.Missing != nil || result.Count != 2 || !result.Frozen {
+		t.Errorf("unexpected helper result: %+v", result)
+	}
+	if !result.Waited || result.Timeout {
+		t.Errorf("unexpected wait result: %+v", result)
+	}
+}
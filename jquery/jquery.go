@@ -0,0 +1,65 @@
+// Package jquery Optional jQuery asset for page reader, the core reader does not depend on it.
+package jquery
+
+import (
+	"context"
+	_ "embed"
+	"github.com/chromedp/chromedp"
+	"github.com/hiscaler/pagereader"
+	"time"
+)
+
+const (
+	ScriptName = "jquery-3.6.0.min.js"
+	GlobalName = "__pageReaderJQuery" // window.__pageReaderJQuery
+)
+
+// Script jQuery v3.6.0
+//
+//go:embed jquery-3.6.0.min.js
+var Script string
+
+// noConflictScript jQuery under window.__pageReaderJQuery, page's own $ and jQuery are restored
+var noConflictScript = Script + "\n;window." + GlobalName + " = jQuery.noConflict(true);"
+
+// Inject Register jQuery to evaluate on every new document, it's available as window.__pageReaderJQuery only,
+// so pages ship their own $ or jQuery are not affected
+func Inject(c *pagereader.ChromeDP) *pagereader.ChromeDP {
+	return c.AddScript(ScriptName, noConflictScript)
+}
+
+// IsLoaded Check window.__pageReaderJQuery is loaded in current page
+func IsLoaded(ctx context.Context, pr *pagereader.PageReader) (loaded bool) {
+	pr.RunTasks(ctx, "JQueryIsLoaded", 1, chromedp.Tasks{
+		chromedp.EvaluateAsDevTools(`typeof window.`+GlobalName+` === "function";`, &loaded),
+	})
+	pr.Logger.Debug("JQuery loaded", pagereader.F("loaded", loaded))
+	return
+}
+
+// Load Evaluate jQuery in current page as window.__pageReaderJQuery and wait it loaded
+func Load(ctx context.Context, pr *pagereader.PageReader, timeout int) (loaded bool, err error) {
+	ts := time.Now().Unix()
+	if IsLoaded(ctx, pr) {
+		return true, nil
+	}
+
+	if timeout < 6 {
+		timeout = 6
+	}
+	err = pr.RunTasks(ctx, "AddJQuery", timeout, chromedp.Tasks{
+		chromedp.EvaluateAsDevTools(noConflictScript, nil),
+	})
+	if err != nil {
+		pr.Logger.Error("AddJQuery failed", pagereader.F("error", err))
+	} else {
+		for {
+			loaded = IsLoaded(ctx, pr)
+			if loaded || time.Now().Unix()-ts >= int64(timeout) {
+				break
+			}
+			time.Sleep(1 * time.Second)
+		}
+	}
+	return
+}
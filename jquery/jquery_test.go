@@ -0,0 +1,30 @@
+package jquery
+
+import (
+	"context"
+	"github.com/hiscaler/pagereader"
+	"strings"
+	"testing"
+)
+
+func TestInject(t *testing.T) {
+	c := Inject(&pagereader.ChromeDP{})
+	scripts := c.Scripts()
+	if len(scripts) != 1 || scripts[0].Name != ScriptName {
+		t.Fatalf("unexpected scripts: %d", len(scripts))
+	}
+	if source := scripts[0].Source; !strings.HasPrefix(source, strings.TrimSpace(Script)) ||
+		!strings.HasSuffix(source, "window.__pageReaderJQuery = jQuery.noConflict(true);") {
+		t.Errorf("jQuery should be injected in noConflict mode, source ends with: %s", source[len(source)-80:])
+	}
+}
+
+func TestLoadWithoutBrowser(t *testing.T) {
+	pr := pagereader.NewPageReader(10, pagereader.NopLogger())
+	if IsLoaded(context.Background(), pr) {
+		t.Error("jQuery should not be loaded without browser")
+	}
+	if loaded, err := Load(context.Background(), pr, 1); loaded || err == nil {
+		t.Errorf("Load should fail without browser, loaded: %v, error: %v", loaded, err)
+	}
+}
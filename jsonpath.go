@@ -0,0 +1,363 @@
+package pagereader
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep A step of JSONPath, selects children of every current value
+type jsonPathStep struct {
+	recursive bool     // ..
+	wildcard  bool     // * or [*]
+	keys      []string // .name, ['name'], ['a','b']
+	indexes   []int    // [0], [-1], [0,2]
+	slice     *[2]*int // [start:end]
+	filter    *jsonPathFilter
+}
+
+// jsonPathFilter [?(@.path)] or [?(@.path op literal)]
+type jsonPathFilter struct {
+	path  string
+	op    string
+	value interface{}
+}
+
+// QueryJSON Query decoded JSON data by JSONPath, supported syntax:
+// $, .name, ['name'], ['a','b'], [0], [-1], [0,2], [1:3], [*], .*, ..name, ..*,
+// [?(@.path)] and [?(@.path op literal)] where op is ==, !=, <, <=, > or >=.
+// Path without "$" is relative to root, e.g. "props.pageProps.product".
+func QueryJSON(data interface{}, path string) ([]interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	values := []interface{}{data}
+	for _, step := range steps {
+		next := make([]interface{}, 0)
+		for _, v := range values {
+			if step.recursive {
+				for _, d := range descendants(v) {
+					next = append(next, step.apply(d)...)
+				}
+			} else {
+				next = append(next, step.apply(v)...)
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	path = strings.TrimSpace(path)
+	switch {
+	case strings.HasPrefix(path, "$"):
+		path = path[1:]
+	case path != "" && path[0] != '.' && path[0] != '[':
+		path = "." + path
+	}
+	steps := make([]jsonPathStep, 0)
+	for i := 0; i < len(path); {
+		step := jsonPathStep{}
+		switch {
+		case strings.HasPrefix(path[i:], ".."):
+			step.recursive = true
+			i += 2
+		case path[i] == '.':
+			i++
+		}
+		switch {
+		case i < len(path) && path[i] == '[':
+			end := bracketEnd(path, i)
+			if end < 0 {
+				return nil, fmt.Errorf("pagereader: unterminated bracket in JSONPath %s", path)
+			}
+			if err := step.parseBracket(strings.TrimSpace(path[i+1 : end])); err != nil {
+				return nil, fmt.Errorf("pagereader: invalid JSONPath %s: %w", path, err)
+			}
+			i = end + 1
+		case i < len(path) && path[i] == '*':
+			step.wildcard = true
+			i++
+		default:
+			j := i
+			for j < len(path) && path[j] != '.' && path[j] != '[' {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("pagereader: empty name in JSONPath %s", path)
+			}
+			step.keys = []string{path[i:j]}
+			i = j
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// bracketEnd Index of "]" matching "[" at start, quotes and nested brackets are skipped
+func bracketEnd(path string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (step *jsonPathStep) parseBracket(expr string) error {
+	switch {
+	case expr == "*":
+		step.wildcard = true
+	case strings.HasPrefix(expr, "?(") && strings.HasSuffix(expr, ")"):
+		return step.parseFilter(strings.TrimSpace(expr[2 : len(expr)-1]))
+	case strings.Contains(expr, ":") && expr[0] != '\'' && expr[0] != '"':
+		parts := strings.SplitN(expr, ":", 2)
+		step.slice = &[2]*int{}
+		for i, part := range parts {
+			if part = strings.TrimSpace(part); part != "" {
+				n, err := strconv.Atoi(part)
+				if err != nil {
+					return err
+				}
+				step.slice[i] = &n
+			}
+		}
+	default:
+		for _, part := range splitTopLevel(expr, ',') {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				return fmt.Errorf("empty bracket item")
+			}
+			if part[0] == '\'' || part[0] == '"' {
+				key, _, err := readJSString(part, 0)
+				if err != nil {
+					return err
+				}
+				step.keys = append(step.keys, key)
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return err
+			}
+			step.indexes = append(step.indexes, n)
+		}
+	}
+	return nil
+}
+
+func (step *jsonPathStep) parseFilter(expr string) error {
+	if !strings.HasPrefix(expr, "@") {
+		return fmt.Errorf("filter must start with @")
+	}
+	filter := &jsonPathFilter{path: "$" + expr[1:]}
+	i, op, err := filterOperator(expr)
+	if err != nil {
+		return err
+	}
+	if op != "" {
+		filter.path = "$" + strings.TrimSpace(expr[1:i])
+		filter.op = op
+		literal := strings.TrimSpace(expr[i+len(op):])
+		if literal != "" && literal[0] == '\'' {
+			s, _, err := readJSString(literal, 0)
+			if err != nil {
+				return err
+			}
+			filter.value = s
+		} else if err := json.Unmarshal([]byte(literal), &filter.value); err != nil {
+			return fmt.Errorf("invalid literal %s", literal)
+		}
+	}
+	if _, err := parseJSONPath(filter.path); err != nil {
+		return err
+	}
+	step.filter = filter
+	return nil
+}
+
+// filterOperator Index of the first comparison operator in expr which is not in a quoted string, empty op if there's none
+func filterOperator(expr string) (int, string, error) {
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '\'', '"':
+			_, end, err := readJSString(expr, i)
+			if err != nil {
+				return 0, "", err
+			}
+			i = end - 1
+		case '=', '!', '<', '>':
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				return i, expr[i : i+2], nil
+			}
+			if c == '<' || c == '>' {
+				return i, string(c), nil
+			}
+		}
+	}
+	return 0, "", nil
+}
+
+func (step jsonPathStep) apply(v interface{}) []interface{} {
+	values := make([]interface{}, 0)
+	switch value := v.(type) {
+	case map[string]interface{}:
+		switch {
+		case step.wildcard || step.filter != nil:
+			for _, k := range sortedKeys(value) {
+				if step.filter == nil || step.filter.match(value[k]) {
+					values = append(values, value[k])
+				}
+			}
+		default:
+			for _, k := range step.keys {
+				if item, ok := value[k]; ok {
+					values = append(values, item)
+				}
+			}
+		}
+	case []interface{}:
+		switch {
+		case step.wildcard || step.filter != nil:
+			for _, item := range value {
+				if step.filter == nil || step.filter.match(item) {
+					values = append(values, item)
+				}
+			}
+		case step.slice != nil:
+			start, end := 0, len(value)
+			if step.slice[0] != nil {
+				start = normalizeIndex(*step.slice[0], len(value))
+			}
+			if step.slice[1] != nil {
+				end = normalizeIndex(*step.slice[1], len(value))
+			}
+			for i := start; i < end && i < len(value); i++ {
+				values = append(values, value[i])
+			}
+		default:
+			for _, i := range step.indexes {
+				// Out of range index matches nothing
+				if i < 0 {
+					i += len(value)
+				}
+				if i >= 0 && i < len(value) {
+					values = append(values, value[i])
+				}
+			}
+		}
+	}
+	return values
+}
+
+func (f jsonPathFilter) match(v interface{}) bool {
+	values, err := QueryJSON(v, f.path)
+	if err != nil || len(values) == 0 {
+		return false
+	}
+	if f.op == "" {
+		return true
+	}
+	actual := values[0]
+	if a, ok := actual.(float64); ok {
+		if b, ok := f.value.(float64); ok {
+			switch f.op {
+			case "==":
+				return a == b
+			case "!=":
+				return a != b
+			case "<":
+				return a < b
+			case "<=":
+				return a <= b
+			case ">":
+				return a > b
+			case ">=":
+				return a >= b
+			}
+		}
+	}
+	if a, ok := actual.(string); ok {
+		if b, ok := f.value.(string); ok {
+			switch f.op {
+			case "==":
+				return a == b
+			case "!=":
+				return a != b
+			case "<":
+				return a < b
+			case "<=":
+				return a <= b
+			case ">":
+				return a > b
+			case ">=":
+				return a >= b
+			}
+		}
+	}
+	// Values can be slices or maps which are not comparable with ==
+	switch f.op {
+	case "==":
+		return reflect.DeepEqual(actual, f.value)
+	case "!=":
+		return !reflect.DeepEqual(actual, f.value)
+	}
+	return false
+}
+
+// descendants Value itself and all nested values, depth first
+func descendants(v interface{}) []interface{} {
+	values := []interface{}{v}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(value) {
+			values = append(values, descendants(value[k])...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			values = append(values, descendants(item)...)
+		}
+	}
+	return values
+}
+
+// normalizeIndex Slice bound counted from the end if negative, clamped to 0
+func normalizeIndex(i, length int) int {
+	if i < 0 {
+		i += length
+		if i < 0 {
+			i = 0
+		}
+	}
+	return i
+}
+
+// sortedKeys Keep results of wildcard in stable order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
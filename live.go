@@ -0,0 +1,207 @@
+package pagereader
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"strings"
+)
+
+//go:embed scripts/live.js
+var liveScript string
+
+// Live query modes, see scripts/live.js
+const (
+	liveText     = "text"
+	liveTextAll  = "text_all"
+	liveVisible  = "visible_text"
+	liveVisibles = "visible_text_all"
+	liveAttr     = "attr"
+	liveAttrAll  = "attr_all"
+	liveProperty = "property"
+	liveCount    = "count"
+)
+
+type liveResult struct {
+	Count   int      `json:"count"`
+	Values  []string `json:"values"`
+	Matched bool     `json:"matched"`
+}
+
+// SetAutoResync Doc will be resynced from the live DOM after WaitReady, Refresh and Do
+func (pr *PageReader) SetAutoResync(resync bool) *PageReader {
+	pr.Config.AutoResync = resync
+	return pr
+}
+
+// Resync Rebuild Doc from the live DOM
+func (pr *PageReader) Resync(ctx context.Context) error {
+	pr.ObtainHtml(ctx)
+	return pr.Error
+}
+
+// resync Resync Doc if AutoResync is on
+func (pr *PageReader) resync(ctx context.Context) {
+	if pr.Config.AutoResync && pr.Error == nil {
+		pr.Resync(ctx)
+	}
+}
+
+// Do Run actions, e.g. click, type or scroll, Doc is resynced after actions if AutoResync is on
+func (pr *PageReader) Do(ctx context.Context, name string, actions ...chromedp.Action) error {
+	if pr.Error = pr.RunTasks(ctx, name, pr.Config.Timeout, actions); pr.Error == nil {
+		pr.resync(ctx)
+	}
+	return pr.Error
+}
+
+// liveLookup Same as lookup but selectors are queried in the browser tab of ctx.
+// Nodes are not waited for, use WaitReady first if need. The chain stops at context or browser errors, a selector
+// which throws, e.g. invalid CSS or XPath, is skipped and its error is returned only if no selector matched.
+func (pr PageReader) liveLookup(ctx context.Context, name string, selectors []string, mode, arg string, attrs ...Field) (m Match, err error) {
+	m = Match{Index: -1}
+	var selectorErr error
+	finish := pr.startExtraction(ctx, name, append(attrs, F("selectors", strings.Join(selectors, ", ")))...)
+	for i, sel := range selectors {
+		pr.trace.add("%s: %s", name, sel)
+		args, _ := json.Marshal([]string{sel, mode, arg})
+		var res liveResult
+		err = chromedp.Run(ctx, pr.ChromeDP.RunWithTimeOut(&ctx, pr.Config.Timeout, chromedp.Tasks{
+			chromedp.Evaluate(fmt.Sprintf("(%s).apply(null, %s)", strings.TrimSpace(liveScript), args), &res),
+		}))
+		pr.debug("Query "+name, append(attrs, F("selector", sel), F("matched", res.Matched), F("values", res.Values), F("error", err))...)
+		if isSelectorError(err) {
+			// A broken selector must not hide the fallback selectors behind it
+			pr.Logger.Warn("Selector failed", F("function", name), F("selector", sel), F("error", err))
+			if selectorErr == nil {
+				selectorErr = err
+			}
+			err = nil
+			continue
+		}
+		if err != nil {
+			break
+		}
+		if res.Matched {
+			m = Match{Selector: sel, Index: i, Count: res.Count, Values: res.Values}
+			break
+		}
+	}
+	if err == nil && !m.Matched() {
+		err = selectorErr
+	}
+	if m.Fallback() {
+		pr.Logger.Warn("Fallback selector matched", F("function", name), F("primary", selectors[0]), F("selector", m.Selector))
+	}
+	finish(m.Matched(), len(strings.Join(m.Values, "")), err)
+	if err != nil {
+		pr.Logger.Error(name+" failed", F("error", err))
+		pr.extractionFailed(ctx, name, strings.Join(selectors, ", "), err)
+	} else if !m.Matched() && mode != liveCount {
+		// Zero count is a valid answer of LiveCount and LiveExists
+		pr.extractionFailed(ctx, name, strings.Join(selectors, ", "), errors.New("no selector matched"))
+	}
+	return
+}
+
+// isSelectorError Whether err is thrown by the query script, not by the context or the browser connection
+func isSelectorError(err error) bool {
+	var exception *runtime.ExceptionDetails
+	return errors.As(err, &exception)
+}
+
+// LiveMatchText Same as MatchText but queried in the live DOM
+func (pr PageReader) LiveMatchText(ctx context.Context, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveText", selectors, liveText, "")
+}
+
+// LiveMatchTextAll Same as MatchTextAll but queried in the live DOM
+func (pr PageReader) LiveMatchTextAll(ctx context.Context, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveTextAll", selectors, liveTextAll, "")
+}
+
+// LiveMatchVisibleText Rendered text of all elements matched by the first selector which has visible text.
+// Text hidden by CSS, e.g. display: none, visibility: hidden, opacity: 0, screen-reader-only clip or off-screen
+// position is skipped, and line breaks are kept between block elements the way a user sees them. aria-hidden text is
+// kept, because it's rendered, e.g. the visible copy of a price next to its screen-reader-only copy.
+func (pr PageReader) LiveMatchVisibleText(ctx context.Context, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveVisibleText", selectors, liveVisible, "")
+}
+
+// LiveMatchVisibleTextAll Rendered text of every element matched by the first selector which matches any element
+func (pr PageReader) LiveMatchVisibleTextAll(ctx context.Context, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveVisibleTextAll", selectors, liveVisibles, "")
+}
+
+// LiveMatchAttr Same as MatchAttr but queried in the live DOM
+func (pr PageReader) LiveMatchAttr(ctx context.Context, attrName string, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveAttr", selectors, liveAttr, attrName, F("attr", attrName))
+}
+
+// LiveMatchAttrAll Same as MatchAttrAll but queried in the live DOM
+func (pr PageReader) LiveMatchAttrAll(ctx context.Context, attrName string, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveAttrAll", selectors, liveAttrAll, attrName, F("attr", attrName))
+}
+
+// LiveMatchProperty DOM property of the first element matched by the first selector which has the property,
+// e.g. value, checked, innerText. Objects are JSON encoded.
+func (pr PageReader) LiveMatchProperty(ctx context.Context, property string, selectors ...string) (Match, error) {
+	return pr.liveLookup(ctx, "LiveProperty", selectors, liveProperty, property, F("property", property))
+}
+
+// LiveText Same as Text but queried in the live DOM
+func (pr PageReader) LiveText(ctx context.Context, selector string, selectors ...string) string {
+	m, _ := pr.LiveMatchText(ctx, append([]string{selector}, selectors...)...)
+	return m.Value()
+}
+
+// LiveTextAll Same as TextAll but queried in the live DOM
+func (pr PageReader) LiveTextAll(ctx context.Context, selector string, selectors ...string) []string {
+	m, _ := pr.LiveMatchTextAll(ctx, append([]string{selector}, selectors...)...)
+	if m.Values == nil {
+		return make([]string, 0)
+	}
+	return m.Values
+}
+
+// LiveVisibleText Rendered text only, see LiveMatchVisibleText
+func (pr PageReader) LiveVisibleText(ctx context.Context, selector string, selectors ...string) string {
+	m, _ := pr.LiveMatchVisibleText(ctx, append([]string{selector}, selectors...)...)
+	return m.Value()
+}
+
+// LiveVisibleTextAll Rendered text of every element, see LiveMatchVisibleText
+func (pr PageReader) LiveVisibleTextAll(ctx context.Context, selector string, selectors ...string) []string {
+	m, _ := pr.LiveMatchVisibleTextAll(ctx, append([]string{selector}, selectors...)...)
+	if m.Values == nil {
+		return make([]string, 0)
+	}
+	return m.Values
+}
+
+// LiveAttr Same as Attr but queried in the live DOM
+func (pr PageReader) LiveAttr(ctx context.Context, selector, attrName string, selectors ...string) (value string, exists bool) {
+	m, _ := pr.LiveMatchAttr(ctx, attrName, append([]string{selector}, selectors...)...)
+	return m.Value(), m.Matched()
+}
+
+// LiveValue Current value property of form control, e.g. input, select and textarea
+func (pr PageReader) LiveValue(ctx context.Context, selector string, selectors ...string) string {
+	m, _ := pr.LiveMatchProperty(ctx, "value", append([]string{selector}, selectors...)...)
+	return m.Value()
+}
+
+// LiveCount Same as Count but queried in the live DOM
+func (pr PageReader) LiveCount(ctx context.Context, selector string, selectors ...string) int {
+	m, _ := pr.liveLookup(ctx, "LiveCount", append([]string{selector}, selectors...), liveCount, "")
+	return m.Count
+}
+
+// LiveExists Same as Exists but queried in the live DOM
+func (pr PageReader) LiveExists(ctx context.Context, selector string, selectors ...string) bool {
+	return pr.LiveCount(ctx, selector, selectors...) > 0
+}
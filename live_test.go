@@ -0,0 +1,136 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"net/url"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestPageReader_LiveTextJoinsNodes(t *testing.T) {
+	const html = `<html><body><p class="price"><span>$</span><span>12</span></p><span class="part">12</span><span class="part">.99</span></body></html>`
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(html)
+	ctx, cancelFunctions := pr.ChromeDP.NewContext(20, NopLogger())
+	defer func() {
+		for i := len(cancelFunctions) - 1; i >= 0; i-- {
+			cancelFunctions[i]()
+		}
+	}()
+	err := chromedp.Run(ctx, chromedp.Navigate("data:text/html,"+url.PathEscape(html)))
+	if errors.Is(err, exec.ErrNotFound) {
+		t.Skip("chrome is not available")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, selector := range []string{".part", "xpath://span[@class='part']", ".price span"} {
+		m, err := pr.LiveMatchText(ctx, selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := pr.Text(selector); m.Value() != expected || m.Count != 2 {
+			t.Errorf("%s: live text %q of %d nodes, expected %q of snapshot", selector, m.Value(), m.Count, expected)
+		}
+	}
+	if v := pr.LiveText(ctx, ".part"); v != "12.99" {
+		t.Errorf("unexpected live text: %s", v)
+	}
+}
+
+func TestPageReader_LiveMatchWithoutBrowser(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	m, err := pr.LiveMatchText(context.Background(), "#a", "#b")
+	if !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if m.Matched() || m.Value() != "" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if selectors := pr.trace.Selectors(); len(selectors) != 1 || selectors[0] != "LiveText: #a" {
+		t.Errorf("chain should stop at error, selectors: %v", selectors)
+	}
+}
+
+func TestPageReader_LiveVisibleTextWithoutBrowser(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	m, err := pr.LiveMatchVisibleText(context.Background(), "#a", "#b")
+	if !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if m.Matched() || m.Value() != "" {
+		t.Errorf("unexpected match: %+v", m)
+	}
+	if selectors := pr.trace.Selectors(); len(selectors) != 1 || selectors[0] != "LiveVisibleText: #a" {
+		t.Errorf("chain should stop at error, selectors: %v", selectors)
+	}
+	if text := pr.LiveVisibleText(context.Background(), "#a"); text != "" {
+		t.Errorf("unexpected text: %s", text)
+	}
+	if texts := pr.LiveVisibleTextAll(context.Background(), "#a"); texts == nil || len(texts) != 0 {
+		t.Errorf("unexpected texts: %#v", texts)
+	}
+}
+
+func TestIsSelectorError(t *testing.T) {
+	exception := &runtime.ExceptionDetails{Text: "Uncaught", Exception: &runtime.RemoteObject{Description: "SyntaxError: '##a' is not a valid selector"}}
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{exception, true},
+		{fmt.Errorf("LiveText: %w", exception), true},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{chromedp.ErrInvalidContext, false},
+	}
+	for _, test := range tests {
+		if v := isSelectorError(test.err); v != test.expected {
+			t.Errorf("isSelectorError(%v) = %v, expected %v", test.err, v, test.expected)
+		}
+	}
+}
+
+func TestPageReader_LiveVisibleText(t *testing.T) {
+	const html = `<html><body>
+<div id="price"><span class="a-offscreen" style="position:absolute;left:-9999px">$12.99 offscreen</span><span aria-hidden="true">$12.99</span></div>
+<div id="desc"><p>First   line</p><p>Second<br>line</p><span style="display:none">none</span><span style="visibility:hidden">invisible</span><span style="opacity:0">transparent</span><span style="position:absolute;width:1px;height:1px;overflow:hidden;clip:rect(0,0,0,0)">sr only</span><template>template</template><script>var script = 1;</script>  tail   <b>bold</b>  text</div>
+<ul><li class="item">A</li><li class="item" hidden>B</li><li class="item">C <i style="display:none">x</i></li></ul>
+</body></html>`
+	pr := NewPageReader(10, NopLogger())
+	ctx, cancelFunctions := pr.ChromeDP.NewContext(20, NopLogger())
+	defer func() {
+		for i := len(cancelFunctions) - 1; i >= 0; i-- {
+			cancelFunctions[i]()
+		}
+	}()
+	err := chromedp.Run(ctx, chromedp.Navigate("data:text/html,"+url.PathEscape(html)))
+	if errors.Is(err, exec.ErrNotFound) {
+		t.Skip("chrome is not available")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	// aria-hidden text is rendered, the off-screen copy for screen readers is not
+	if v := pr.LiveVisibleText(ctx, "#price"); v != "$12.99" {
+		t.Errorf("unexpected price: %q", v)
+	}
+	if v := pr.LiveVisibleText(ctx, "#desc"); v != "First line\nSecond\nline\ntail bold text" {
+		t.Errorf("unexpected visible text: %q", v)
+	}
+	if v := pr.LiveVisibleTextAll(ctx, ".item"); !reflect.DeepEqual(v, []string{"A", "", "C"}) {
+		t.Errorf("unexpected visible texts: %q", v)
+	}
+	if v := pr.LiveVisibleText(ctx, ".item"); v != "A\nC" {
+		t.Errorf("unexpected joined visible text: %q", v)
+	}
+	if m, err := pr.LiveMatchVisibleText(ctx, "#desc span", "#price"); err != nil || m.Selector != "#price" {
+		t.Errorf("hidden text should fall back to the next selector: %+v, error: %v", m, err)
+	}
+}
@@ -0,0 +1,144 @@
+package pagereader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Field Key/value pair of a log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F Shortcut to create a Field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger Leveled logger with key/value fields
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+}
+
+// logf Adapter for printf style loggers, e.g. chromedp.WithLogf
+func logf(logger Logger, level Level) func(format string, v ...interface{}) {
+	return func(format string, v ...interface{}) {
+		logger.Log(level, fmt.Sprintf(format, v...))
+	}
+}
+
+type levelLogger struct {
+	level Level
+	log   func(level Level, msg string, fields ...Field)
+}
+
+func (l levelLogger) Log(level Level, msg string, fields ...Field) {
+	if level >= l.level {
+		l.log(level, msg, fields...)
+	}
+}
+
+func (l levelLogger) Debug(msg string, fields ...Field) {
+	l.Log(LevelDebug, msg, fields...)
+}
+
+func (l levelLogger) Info(msg string, fields ...Field) {
+	l.Log(LevelInfo, msg, fields...)
+}
+
+func (l levelLogger) Warn(msg string, fields ...Field) {
+	l.Log(LevelWarn, msg, fields...)
+}
+
+func (l levelLogger) Error(msg string, fields ...Field) {
+	l.Log(LevelError, msg, fields...)
+}
+
+// NewStdLogger Standard library logger adapter, output like: [INFO] Open success url=https://www.example.com
+func NewStdLogger(logger *log.Logger, level Level) Logger {
+	return levelLogger{
+		level: level,
+		log: func(level Level, msg string, fields ...Field) {
+			sb := strings.Builder{}
+			sb.WriteString("[" + strings.ToUpper(level.String()) + "] " + msg)
+			for _, field := range fields {
+				value := fmt.Sprint(field.Value)
+				if strings.ContainsAny(value, " \t\r\n\"=") || value == "" {
+					value = fmt.Sprintf("%q", value)
+				}
+				sb.WriteString(" " + field.Key + "=" + value)
+			}
+			logger.Print(sb.String())
+		},
+	}
+}
+
+// NewJSONLogger JSON lines logger, every entry has time, level and msg keys
+func NewJSONLogger(w io.Writer, level Level) Logger {
+	mu := &sync.Mutex{}
+	return levelLogger{
+		level: level,
+		log: func(level Level, msg string, fields ...Field) {
+			entry := make(map[string]interface{}, len(fields)+3)
+			for _, field := range fields {
+				value := field.Value
+				switch v := value.(type) {
+				case json.Marshaler:
+				case error:
+					value = v.Error()
+				case fmt.Stringer:
+					value = v.String()
+				}
+				entry[field.Key] = value
+			}
+			entry["time"] = time.Now().Format(time.RFC3339Nano)
+			entry["level"] = level.String()
+			entry["msg"] = msg
+			b, err := json.Marshal(entry)
+			if err != nil {
+				b, _ = json.Marshal(map[string]interface{}{"time": entry["time"], "level": entry["level"], "msg": msg, "error": err.Error()})
+			}
+			mu.Lock()
+			w.Write(append(b, '\n'))
+			mu.Unlock()
+		},
+	}
+}
+
+// NopLogger Discard all logs
+func NopLogger() Logger {
+	return levelLogger{level: LevelError + 1, log: func(level Level, msg string, fields ...Field) {}}
+}
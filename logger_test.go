@@ -0,0 +1,48 @@
+package pagereader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := NewStdLogger(log.New(&b, "", 0), LevelInfo)
+	logger.Debug("hidden")
+	logger.Info("Open success", F("url", "https://example.com"), F("title", "Hello world"))
+	if s := strings.TrimSpace(b.String()); s != `[INFO] Open success url=https://example.com title="Hello world"` {
+		t.Errorf("unexpected output: %s", s)
+	}
+}
+
+func TestJSONLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := NewJSONLogger(&b, LevelDebug)
+	logger.Error("Open failed", F("error", errors.New("timeout")), F("times", 2))
+	entry := make(map[string]interface{})
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "error" || entry["msg"] != "Open failed" || entry["error"] != "timeout" || entry["times"] != float64(2) {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
+
+func TestPageReader_DebugLogs(t *testing.T) {
+	var b bytes.Buffer
+	pr := NewPageReader(10, NewStdLogger(log.New(&b, "", 0), LevelInfo))
+	pr.SetHtml(`<html><body><h1>Hello</h1></body></html>`)
+	pr.Text("h1")
+	if strings.Contains(b.String(), "Query Text") {
+		t.Errorf("queries should not be logged out of Debug mode: %s", b.String())
+	}
+	pr.Debug = true
+	pr.Text("h1")
+	if !strings.Contains(b.String(), "[INFO] Query Text selector=h1") {
+		t.Errorf("queries should be logged with the default logger in Debug mode: %s", b.String())
+	}
+}
@@ -0,0 +1,89 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrBotDetected = errors.New("pagereader: bot detected")
+
+// Error classes
+const (
+	ErrorClassNone        = "none"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassException   = "javascript_exception"
+	ErrorClassBotDetected = "bot_detected"
+	ErrorClassNetwork     = "network"
+	ErrorClassOther       = "other"
+)
+
+// ErrorClass Classify error for metrics labels
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ErrorClassNone
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, ErrJavaScriptException):
+		return ErrorClassException
+	case errors.Is(err, ErrBotDetected):
+		return ErrorClassBotDetected
+	case strings.Contains(err.Error(), "net::ERR_"):
+		return ErrorClassNetwork
+	}
+	return ErrorClassOther
+}
+
+// Metrics Metrics hook, see the metrics subpackage for Prometheus collector
+type Metrics interface {
+	OpenStarted(host string)
+	OpenFinished(host string, err error)
+	ObserveNavigation(host string, duration time.Duration) // Duration of the navigate action only
+	ObserveExtraction(kind string, duration time.Duration)
+	IncRetry(host string)
+	IncBotDetection(host string)
+	AddActiveTabs(delta int) // Tabs being read, the tab of the outermost Open and every captured or followed popup
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) OpenStarted(host string) {}
+
+func (noopMetrics) OpenFinished(host string, err error) {}
+
+func (noopMetrics) ObserveNavigation(host string, duration time.Duration) {}
+
+func (noopMetrics) ObserveExtraction(kind string, duration time.Duration) {}
+
+func (noopMetrics) IncRetry(host string) {}
+
+func (noopMetrics) IncBotDetection(host string) {}
+
+func (noopMetrics) AddActiveTabs(delta int) {}
+
+// SetMetrics Set metrics hook, nil to disable
+func (pr *PageReader) SetMetrics(metrics Metrics) *PageReader {
+	pr.Metrics = metrics
+	return pr
+}
+
+// BotDetector Return true if the page is a bot detection page, e.g. captcha
+type BotDetector func(pr *PageReader) bool
+
+// SetBotDetector Open will fail with ErrBotDetected when detector returns true, nil to disable
+func (pr *PageReader) SetBotDetector(detector BotDetector) *PageReader {
+	pr.botDetector = detector
+	return pr
+}
+
+func (pr PageReader) metrics() Metrics {
+	if pr.Metrics == nil {
+		return noopMetrics{}
+	}
+	return pr.Metrics
+}
@@ -0,0 +1,125 @@
+// Package metrics Prometheus collector for page reader, it's a separate module so the core module does not depend on Prometheus.
+//
+//	collector := metrics.NewCollector("scraper")
+//	prometheus.MustRegister(collector)
+//	pr.SetMetrics(collector)
+package metrics
+
+import (
+	"github.com/hiscaler/pagereader"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Collector Implement pagereader.Metrics and prometheus.Collector
+type Collector struct {
+	opens              *prometheus.CounterVec
+	successes          *prometheus.CounterVec
+	failures           *prometheus.CounterVec
+	navigationDuration *prometheus.HistogramVec
+	extractionDuration *prometheus.HistogramVec
+	retries            *prometheus.CounterVec
+	botDetections      *prometheus.CounterVec
+	activeTabs         prometheus.Gauge
+}
+
+// NewCollector Create collector, metric names are prefixed with namespace_pagereader_
+func NewCollector(namespace string) *Collector {
+	const subsystem = "pagereader"
+	return &Collector{
+		opens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "opens_total",
+			Help: "Total number of page opens.",
+		}, []string{"host"}),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "open_successes_total",
+			Help: "Total number of successful page opens.",
+		}, []string{"host"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "open_failures_total",
+			Help: "Total number of failed page opens by error class.",
+		}, []string{"host", "error_class"}),
+		navigationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name:    "navigation_duration_seconds",
+			Help:    "Page navigation duration in seconds.",
+			Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60},
+		}, []string{"host"}),
+		extractionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name:    "extraction_duration_seconds",
+			Help:    "Extraction duration in seconds.",
+			Buckets: []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5},
+		}, []string{"kind"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "retries_total",
+			Help: "Total number of page open retries.",
+		}, []string{"host"}),
+		botDetections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "bot_detections_total",
+			Help: "Total number of bot detection pages.",
+		}, []string{"host"}),
+		activeTabs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "active_tabs",
+			Help: "Number of browser tabs being read, including popups.",
+		}),
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.opens, c.successes, c.failures,
+		c.navigationDuration, c.extractionDuration,
+		c.retries, c.botDetections, c.activeTabs,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (c *Collector) OpenStarted(host string) {
+	c.opens.WithLabelValues(host).Inc()
+}
+
+func (c *Collector) OpenFinished(host string, err error) {
+	if err == nil {
+		c.successes.WithLabelValues(host).Inc()
+	} else {
+		c.failures.WithLabelValues(host, pagereader.ErrorClass(err)).Inc()
+	}
+}
+
+func (c *Collector) ObserveNavigation(host string, duration time.Duration) {
+	c.navigationDuration.WithLabelValues(host).Observe(duration.Seconds())
+}
+
+func (c *Collector) ObserveExtraction(kind string, duration time.Duration) {
+	c.extractionDuration.WithLabelValues(kind).Observe(duration.Seconds())
+}
+
+func (c *Collector) IncRetry(host string) {
+	c.retries.WithLabelValues(host).Inc()
+}
+
+func (c *Collector) IncBotDetection(host string) {
+	c.botDetections.WithLabelValues(host).Inc()
+}
+
+func (c *Collector) AddActiveTabs(delta int) {
+	c.activeTabs.Add(float64(delta))
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"github.com/hiscaler/pagereader"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("test")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+
+	var m pagereader.Metrics = c
+	m.OpenStarted("example.com")
+	m.AddActiveTabs(1)
+	m.ObserveNavigation("example.com", 2*time.Second)
+	m.IncRetry("example.com")
+	m.OpenFinished("example.com", context.DeadlineExceeded)
+	m.OpenFinished("example.com", nil)
+	m.AddActiveTabs(-1)
+	m.IncBotDetection("example.com")
+
+	if v := testutil.ToFloat64(c.failures.WithLabelValues("example.com", pagereader.ErrorClassTimeout)); v != 1 {
+		t.Errorf("failures = %v", v)
+	}
+	if v := testutil.ToFloat64(c.successes.WithLabelValues("example.com")); v != 1 {
+		t.Errorf("successes = %v", v)
+	}
+	if v := testutil.ToFloat64(c.activeTabs); v != 0 {
+		t.Errorf("active tabs = %v", v)
+	}
+	if n := testutil.CollectAndCount(c, "test_pagereader_navigation_duration_seconds"); n != 1 {
+		t.Errorf("navigation duration series = %d", n)
+	}
+}
+
+func TestCollector_Extraction(t *testing.T) {
+	c := NewCollector("")
+	pr := pagereader.NewPageReader(10, pagereader.NopLogger())
+	pr.SetMetrics(c)
+	pr.SetHtml(`<html><body><a id="link" href="/a">A</a></body></html>`)
+	pr.Text("#link")
+	pr.Attr("#link", "href")
+	if n := testutil.CollectAndCount(c, "pagereader_extraction_duration_seconds"); n != 2 {
+		t.Errorf("extraction duration series = %d", n)
+	}
+}
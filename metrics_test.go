@@ -0,0 +1,63 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err   error
+		class string
+	}{
+		{nil, ErrorClassNone},
+		{fmt.Errorf("open: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{context.Canceled, ErrorClassCanceled},
+		{fmt.Errorf("%w: TypeError", ErrJavaScriptException), ErrorClassException},
+		{ErrBotDetected, ErrorClassBotDetected},
+		{errors.New("page load error net::ERR_NAME_NOT_RESOLVED"), ErrorClassNetwork},
+		{errors.New("unknown"), ErrorClassOther},
+	}
+	for _, test := range tests {
+		if class := ErrorClass(test.err); class != test.class {
+			t.Errorf("ErrorClass(%v) = %s, expected %s", test.err, class, test.class)
+		}
+	}
+}
+
+// countMetrics Count opens, navigations and active tabs
+type countMetrics struct {
+	noopMetrics
+	started, finished, navigations, tabs, maxTabs int
+}
+
+func (m *countMetrics) OpenStarted(host string) { m.started++ }
+
+func (m *countMetrics) OpenFinished(host string, err error) { m.finished++ }
+
+func (m *countMetrics) ObserveNavigation(host string, duration time.Duration) { m.navigations++ }
+
+func (m *countMetrics) AddActiveTabs(delta int) {
+	m.tabs += delta
+	if m.tabs > m.maxTabs {
+		m.maxTabs = m.tabs
+	}
+}
+
+func TestPageReader_OpenMetrics(t *testing.T) {
+	metrics := &countMetrics{}
+	pr := NewPageReader(10, NopLogger())
+	pr.SetMetrics(metrics)
+	// Caller's Notify must not hide the open
+	ctx := ContextWithNotify(noBrowserContext(t), NewNotify("Crawl", "job"))
+	if _, err := pr.Open(ctx, "https://example.com/", 5); !errors.Is(err, errNoBrowser) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Navigation is not observed, the tab never navigated
+	if metrics.started != 1 || metrics.finished != 1 || metrics.navigations != 0 || metrics.tabs != 0 || metrics.maxTabs != 1 {
+		t.Errorf("unexpected metrics: %+v", metrics)
+	}
+}
@@ -0,0 +1,227 @@
+package pagereader
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Document Main document request and response of the page
+type Document struct {
+	RequestID     network.RequestID
+	Request       *network.Request
+	Response      *network.Response
+	Time          time.Time // Request time
+	Body          []byte    `json:"-"`             // Response body, only fetched when need, e.g. write WARC
+	BodyTruncated bool      `json:"bodyTruncated"` // Body is unavailable, e.g. evicted by Chrome or fetch failed
+}
+
+// Resource Network request made by the page
+type Resource struct {
+	RequestID     network.RequestID `json:"requestId"`
+	URL           string            `json:"url"`
+	Method        string            `json:"method"`
+	Type          string            `json:"type"` // Document, Script, Image, XHR...
+	Status        int64             `json:"status"`
+	MimeType      string            `json:"mimeType"`
+	Size          int64             `json:"size"` // Bytes received from network, include headers
+	StartTime     time.Time         `json:"startTime"`
+	Duration      float64           `json:"duration"` // Milliseconds from request sent to loading finished or failed
+	FromCache     bool              `json:"fromCache"`
+	Blocked       bool              `json:"blocked"`
+	BlockedReason string            `json:"blockedReason,omitempty"`
+	Canceled      bool              `json:"canceled"`
+	Finished      bool              `json:"finished"`
+	Error         string            `json:"error,omitempty"`
+	start         time.Time         // Monotonic time of request sent
+}
+
+func (r Resource) Failed() bool {
+	return r.Error != "" || r.Blocked || r.Status >= 400
+}
+
+type networkCollector struct {
+	sync.Mutex
+	document  *Document
+	resources []*Resource
+	index     map[network.RequestID]*Resource
+}
+
+// listen Collect network events until ctx canceled
+func (c *networkCollector) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		c.handle(ctx, ev)
+	})
+}
+
+func (c *networkCollector) handle(ctx context.Context, ev interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	if c.index == nil {
+		c.index = make(map[network.RequestID]*Resource)
+	}
+	switch e := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if previous, ok := c.index[e.RequestID]; ok && e.RedirectResponse != nil {
+			previous.Status = e.RedirectResponse.Status
+			previous.MimeType = e.RedirectResponse.MimeType
+			previous.Size = int64(e.RedirectResponse.EncodedDataLength)
+			previous.Finished = true
+			if e.Timestamp != nil {
+				previous.finish(e.Timestamp.Time())
+			}
+		}
+		resource := &Resource{
+			RequestID: e.RequestID,
+			URL:       e.Request.URL,
+			Method:    e.Request.Method,
+			Type:      string(e.Type),
+			StartTime: time.Now(),
+		}
+		if e.WallTime != nil {
+			resource.StartTime = e.WallTime.Time()
+		}
+		if e.Timestamp != nil {
+			resource.start = e.Timestamp.Time()
+		}
+		c.resources = append(c.resources, resource)
+		c.index[e.RequestID] = resource
+		if e.Type != network.ResourceTypeDocument || !isMainFrame(ctx, e.FrameID) {
+			return
+		}
+		t := time.Now()
+		if e.WallTime != nil {
+			t = e.WallTime.Time()
+		}
+		// Redirect request use the same request id, keep the last one
+		c.document = &Document{RequestID: e.RequestID, Request: e.Request, Time: t}
+	case *network.EventResponseReceived:
+		if resource, ok := c.index[e.RequestID]; ok {
+			resource.Status = e.Response.Status
+			resource.MimeType = e.Response.MimeType
+			resource.FromCache = resource.FromCache || e.Response.FromDiskCache || e.Response.FromPrefetchCache || e.Response.FromServiceWorker
+		}
+		if c.document != nil && c.document.RequestID == e.RequestID {
+			c.document.Response = e.Response
+		}
+	case *network.EventRequestServedFromCache:
+		if resource, ok := c.index[e.RequestID]; ok {
+			resource.FromCache = true
+		}
+	case *network.EventLoadingFinished:
+		if resource, ok := c.index[e.RequestID]; ok {
+			resource.Size = int64(e.EncodedDataLength)
+			resource.Finished = true
+			if e.Timestamp != nil {
+				resource.finish(e.Timestamp.Time())
+			}
+		}
+	case *network.EventLoadingFailed:
+		if resource, ok := c.index[e.RequestID]; ok {
+			resource.Error = e.ErrorText
+			resource.Canceled = e.Canceled
+			if e.BlockedReason != "" {
+				resource.Blocked = true
+				resource.BlockedReason = string(e.BlockedReason)
+			}
+			if e.Timestamp != nil {
+				resource.finish(e.Timestamp.Time())
+			}
+		}
+	}
+}
+
+func (c *networkCollector) Document() *Document {
+	c.Lock()
+	defer c.Unlock()
+	if c.document == nil {
+		return nil
+	}
+	document := *c.document
+	return &document
+}
+
+func (c *networkCollector) Resources() []Resource {
+	c.Lock()
+	defer c.Unlock()
+	resources := make([]Resource, len(c.resources))
+	for i, resource := range c.resources {
+		resources[i] = *resource
+	}
+	return resources
+}
+
+func (r *Resource) finish(t time.Time) {
+	if !r.start.IsZero() {
+		r.Duration = float64(t.Sub(r.start)) / float64(time.Millisecond)
+	}
+}
+
+// FailedResources Resources failed, blocked or responded with 4xx/5xx
+func (pr PageReader) FailedResources() []Resource {
+	resources := make([]Resource, 0)
+	for _, r := range pr.Resources {
+		if r.Failed() {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// ThirdPartyResources Resources not from the page host or it's subdomains
+func (pr PageReader) ThirdPartyResources() []Resource {
+	resources := make([]Resource, 0)
+	host := strings.TrimPrefix(hostnameOf(pr.URL), "www.")
+	if host == "" {
+		return resources
+	}
+	for _, r := range pr.Resources {
+		h := hostnameOf(r.URL)
+		if h == "" || h == host || strings.HasSuffix(h, "."+host) {
+			continue
+		}
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+func hostnameOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+func isMainFrame(ctx context.Context, frameID cdp.FrameID) bool {
+	cc := chromedp.FromContext(ctx)
+	return cc != nil && cc.Target != nil && string(frameID) == string(cc.Target.TargetID)
+}
+
+// fetchBody Fetch response body of the document in timeout seconds, Chrome may evict it from buffer.
+// BodyTruncated is set if the body is unavailable.
+func (d *Document) fetchBody(ctx context.Context, timeout int) error {
+	if d == nil || d.Response == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeoutDuration(timeout))
+	defer cancel()
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) (err error) {
+		d.Body, err = network.GetResponseBody(d.RequestID).Do(ctx)
+		return
+	}))
+	d.BodyTruncated = err != nil
+	return err
+}
+
+// StatusCode HTTP status code of the main document, 0 if unknown
+func (pr PageReader) StatusCode() int {
+	if pr.Document == nil || pr.Document.Response == nil {
+		return 0
+	}
+	return int(pr.Document.Response.Status)
+}
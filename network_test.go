@@ -0,0 +1,45 @@
+package pagereader
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"testing"
+	"time"
+)
+
+func TestNetworkCollector_Resources(t *testing.T) {
+	c := &networkCollector{}
+	ctx := context.Background()
+	now := time.Now()
+	at := func(ms int) *cdp.MonotonicTime {
+		t := cdp.MonotonicTime(now.Add(time.Duration(ms) * time.Millisecond))
+		return &t
+	}
+	c.handle(ctx, &network.EventRequestWillBeSent{RequestID: "1", Type: network.ResourceTypeScript, Timestamp: at(0), Request: &network.Request{URL: "https://cdn.example.net/a.js", Method: "GET"}})
+	c.handle(ctx, &network.EventResponseReceived{RequestID: "1", Response: &network.Response{Status: 200, MimeType: "application/javascript", FromDiskCache: true}})
+	c.handle(ctx, &network.EventLoadingFinished{RequestID: "1", Timestamp: at(120), EncodedDataLength: 2048})
+	c.handle(ctx, &network.EventRequestWillBeSent{RequestID: "2", Type: network.ResourceTypeImage, Timestamp: at(10), Request: &network.Request{URL: "https://www.example.com/a.png", Method: "GET"}})
+	c.handle(ctx, &network.EventLoadingFailed{RequestID: "2", Timestamp: at(30), ErrorText: "net::ERR_BLOCKED_BY_CLIENT", BlockedReason: network.BlockedReasonInspector})
+
+	resources := c.Resources()
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(resources))
+	}
+	script := resources[0]
+	if !script.Finished || !script.FromCache || script.Size != 2048 || script.Status != 200 || int(script.Duration) != 120 {
+		t.Errorf("unexpected script resource: %+v", script)
+	}
+	image := resources[1]
+	if !image.Blocked || !image.Failed() || int(image.Duration) != 20 {
+		t.Errorf("unexpected image resource: %+v", image)
+	}
+
+	pr := PageReader{URL: "https://www.example.com/", Resources: resources}
+	if n := len(pr.FailedResources()); n != 1 {
+		t.Errorf("expected 1 failed resource, got %d", n)
+	}
+	if thirdParty := pr.ThirdPartyResources(); len(thirdParty) != 1 || thirdParty[0].URL != "https://cdn.example.net/a.js" {
+		t.Errorf("unexpected third party resources: %+v", thirdParty)
+	}
+}
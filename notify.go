@@ -1,7 +1,12 @@
 package pagereader
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,6 +17,29 @@ type Notify struct {
 	EndTime      time.Time
 	Logs         []string
 	Error        error
+	Children     []*Notify // Sub-steps spans
+	parent       *Notify
+	mu           sync.Mutex // Guards Logs and Children, listener goroutines add logs and spans during Open
+}
+
+type notifyContextKey struct{}
+
+// ContextWithNotify Notifies created with the returned context will be children of n
+func ContextWithNotify(ctx context.Context, n *Notify) context.Context {
+	return context.WithValue(ctx, notifyContextKey{}, n)
+}
+
+func NotifyFromContext(ctx context.Context) *Notify {
+	n, _ := ctx.Value(notifyContextKey{}).(*Notify)
+	return n
+}
+
+// newNotify Create notify as child of the notify in ctx if exists
+func newNotify(ctx context.Context, functionName, markName string) *Notify {
+	if parent := NotifyFromContext(ctx); parent != nil {
+		return parent.Child(functionName, markName)
+	}
+	return NewNotify(functionName, markName)
 }
 
 func NewNotify(functionName, markName string) *Notify {
@@ -23,17 +51,93 @@ func NewNotify(functionName, markName string) *Notify {
 	}
 }
 
-func (n *Notify) AddLog(msg string) *Notify {
-	n.Logs = append(n.Logs, fmt.Sprintf("%s > %s", time.Now().Format("2006-01-02 15:04:05"), msg))
+// Child Start a sub-step span
+func (n *Notify) Child(functionName, markName string) *Notify {
+	child := NewNotify(functionName, markName)
+	child.parent = n
+	n.mu.Lock()
+	n.Children = append(n.Children, child)
+	n.mu.Unlock()
+	return child
+}
+
+// Finish End the span
+func (n *Notify) Finish(err error) *Notify {
+	n.EndTime = time.Now()
+	n.Error = err
 	return n
 }
 
+func (n *Notify) IsRoot() bool {
+	return n.parent == nil
+}
+
+func (n *Notify) AddLog(msg string) *Notify {
+	return n.addLog(fmt.Sprintf("%s > %s", time.Now().Format("2006-01-02 15:04:05"), msg))
+}
+
 func (n *Notify) AddLogf(format string, v ...interface{}) *Notify {
-	n.Logs = append(n.Logs, fmt.Sprintf(time.Now().Format("2006-01-02 15:04:05")+" > "+format, v...))
+	return n.addLog(fmt.Sprintf(time.Now().Format("2006-01-02 15:04:05")+" > "+format, v...))
+}
+
+func (n *Notify) addLog(log string) *Notify {
+	n.mu.Lock()
+	n.Logs = append(n.Logs, log)
+	n.mu.Unlock()
 	return n
 }
 
-func (n Notify) String() string {
+// snapshot Copy of Logs and Children, they may be appended by other goroutines
+func (n *Notify) snapshot() (logs []string, children []*Notify) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	logs = append(make([]string, 0, len(n.Logs)), n.Logs...)
+	children = append(make([]*Notify, 0, len(n.Children)), n.Children...)
+	return
+}
+
+// Fields Notify as log fields, children spans are in the children field
+func (n *Notify) Fields() []Field {
+	event := n.Event()
+	fields := []Field{
+		F("function", event.Function),
+		F("mark", event.Mark),
+		F("start", event.Start),
+		F("end", event.End),
+		F("seconds", event.Duration),
+		F("success", event.Success),
+	}
+	if n.Error != nil {
+		fields = append(fields, F("error", n.Error))
+	}
+	if len(event.Logs) > 0 {
+		fields = append(fields, F("logs", event.Logs))
+	}
+	if len(event.Children) > 0 {
+		fields = append(fields, F("children", notifyChildren{n}))
+	}
+	return fields
+}
+
+// notifyChildren Children spans as a log field, text loggers write the timeline and JSON loggers write the events
+type notifyChildren struct {
+	n *Notify
+}
+
+func (c notifyChildren) String() string {
+	s := ""
+	_, children := c.n.snapshot()
+	for _, child := range children {
+		s += child.timeline(c.n.StartingTime, "", 0)
+	}
+	return strings.TrimSuffix(s, "\n")
+}
+
+func (c notifyChildren) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.n.Event().Children)
+}
+
+func (n *Notify) String() string {
 	format := `
 Function Name: %s
     Mark Name: %s
@@ -41,25 +145,176 @@ Starting Time: %s
      End Time: %s
       Seconds: %.2f seconds
       Success: %v`
-	if n.EndTime.IsZero() {
-		n.EndTime = time.Now()
+	endTime := n.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now()
 	}
-	seconds := n.EndTime.Sub(n.StartingTime).Seconds()
-	values := []interface{}{n.FunctionName, n.MarkName, n.StartingTime, n.EndTime, seconds, n.Error == nil}
+	seconds := endTime.Sub(n.StartingTime).Seconds()
+	values := []interface{}{n.FunctionName, n.MarkName, n.StartingTime, endTime, seconds, n.Error == nil}
 	if n.Error != nil {
 		format += `
        Error: %s`
 		values = append(values, n.Error)
 	}
-	if len(n.Logs) > 0 {
+	logs, children := n.snapshot()
+	if len(logs) > 0 {
 		format += `
          Logs:
 %s`
-		logs := ""
-		for _, log := range n.Logs {
-			logs += "               " + log + "\n"
+		s := ""
+		for _, log := range logs {
+			s += "               " + log + "\n"
 		}
-		values = append(values, logs)
+		values = append(values, s)
+	}
+	if len(children) > 0 {
+		format += `
+     Timeline:
+%s`
+		values = append(values, n.timeline(n.StartingTime, "               ", 0))
 	}
 	return fmt.Sprintf(format, values...)
 }
+
+// timeline Indented spans, offset is relative to start, every line is prefixed with prefix
+func (n *Notify) timeline(start time.Time, prefix string, depth int) string {
+	endTime := n.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	s := fmt.Sprintf("%s%s+%.2fs %s %s %.2fs", prefix, strings.Repeat("  ", depth), n.StartingTime.Sub(start).Seconds(), n.FunctionName, n.MarkName, endTime.Sub(n.StartingTime).Seconds())
+	if n.Error != nil {
+		s += " [ Error: " + n.Error.Error() + " ]"
+	}
+	s += "\n"
+	_, children := n.snapshot()
+	for _, child := range children {
+		s += child.timeline(start, prefix, depth+1)
+	}
+	return s
+}
+
+// NotifyEvent Structured Notify record which is emitted to sinks
+type NotifyEvent struct {
+	Function string        `json:"function"`
+	Mark     string        `json:"mark"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration float64       `json:"duration"` // seconds
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+	Logs     []string      `json:"logs,omitempty"`
+	Children []NotifyEvent `json:"children,omitempty"`
+}
+
+func (n *Notify) Event() NotifyEvent {
+	logs, children := n.snapshot()
+	endTime := n.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	event := NotifyEvent{
+		Function: n.FunctionName,
+		Mark:     n.MarkName,
+		Start:    n.StartingTime,
+		End:      endTime,
+		Duration: endTime.Sub(n.StartingTime).Seconds(),
+		Success:  n.Error == nil,
+		Logs:     logs,
+	}
+	if n.Error != nil {
+		event.Error = n.Error.Error()
+	}
+	for _, child := range children {
+		event.Children = append(event.Children, child.Event())
+	}
+	return event
+}
+
+// JSON Notify with children spans as JSON
+func (n *Notify) JSON() ([]byte, error) {
+	return json.Marshal(n.Event())
+}
+
+// NotifySink Receive Notify events
+type NotifySink interface {
+	Emit(event NotifyEvent) error
+}
+
+// NotifySinkFunc Callback hook sink
+type NotifySinkFunc func(event NotifyEvent) error
+
+func (f NotifySinkFunc) Emit(event NotifyEvent) error {
+	return f(event)
+}
+
+// JSONLinesSink Append events to file as JSON Lines
+type JSONLinesSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewJSONLinesSink(filename string) (*JSONLinesSink, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesSink{file: file}, nil
+}
+
+func (s *JSONLinesSink) Emit(event NotifyEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(b, '\n'))
+	return err
+}
+
+func (s *JSONLinesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// RingBufferSink Keep the latest events in memory, it's useful for tests
+type RingBufferSink struct {
+	mu     sync.Mutex
+	events []NotifyEvent
+	next   int
+	full   bool
+}
+
+func NewRingBufferSink(size int) *RingBufferSink {
+	if size <= 0 {
+		size = 100
+	}
+	return &RingBufferSink{events: make([]NotifyEvent, size)}
+}
+
+func (s *RingBufferSink) Emit(event NotifyEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[s.next] = event
+	s.next = (s.next + 1) % len(s.events)
+	if s.next == 0 {
+		s.full = true
+	}
+	return nil
+}
+
+// Events Events from oldest to newest
+func (s *RingBufferSink) Events() []NotifyEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full {
+		events := make([]NotifyEvent, s.next)
+		copy(events, s.events[:s.next])
+		return events
+	}
+	events := make([]NotifyEvent, 0, len(s.events))
+	events = append(events, s.events[s.next:]...)
+	return append(events, s.events[:s.next]...)
+}
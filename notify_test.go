@@ -1,7 +1,14 @@
 package pagereader
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -11,3 +18,156 @@ func TestNotify_String(t *testing.T) {
 	notify.AddLogf("abc%sef", "d")
 	fmt.Println(notify.String())
 }
+
+func TestRingBufferSink(t *testing.T) {
+	sink := NewRingBufferSink(2)
+	pr := NewPageReader(10, NopLogger())
+	pr.AddNotifySink(sink)
+	for _, mark := range []string{"a", "b", "c"} {
+		pr.publish(NewNotify("Test", mark))
+	}
+	events := sink.Events()
+	if len(events) != 2 || events[0].Mark != "b" || events[1].Mark != "c" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
+
+func TestNotifySinkFunc(t *testing.T) {
+	var event NotifyEvent
+	pr := NewPageReader(10, NopLogger())
+	pr.AddNotifySink(NotifySinkFunc(func(e NotifyEvent) error {
+		event = e
+		return nil
+	}))
+	notify := NewNotify("Open", "https://example.com")
+	notify.Error = errors.New("timeout")
+	pr.publish(notify)
+	if event.Function != "Open" || event.Success || event.Error != "timeout" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "notify.jsonl")
+	sink, err := NewJSONLinesSink(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Emit(NewNotify("Open", "a").AddLog("hello").Event())
+	sink.Emit(NewNotify("Open", "b").Event())
+	sink.Close()
+	b, _ := os.ReadFile(filename)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var event NotifyEvent
+	if err = json.Unmarshal([]byte(lines[0]), &event); err != nil || event.Mark != "a" || len(event.Logs) != 1 {
+		t.Errorf("unexpected event: %+v, error: %v", event, err)
+	}
+}
+
+func TestNotify_Children(t *testing.T) {
+	sink := NewRingBufferSink(10)
+	pr := NewPageReader(10, NopLogger())
+	pr.AddNotifySink(sink)
+
+	root := NewNotify("Open", "https://example.com")
+	ctx := ContextWithNotify(context.Background(), root)
+	navigate := newNotify(ctx, "Navigate", "https://example.com")
+	navigate.Child("Retry", "#2").Finish(errors.New("timeout"))
+	navigate.Finish(nil)
+	pr.publish(navigate)
+	if len(sink.Events()) != 0 {
+		t.Fatalf("child span should not be published")
+	}
+	pr.publish(root)
+	events := sink.Events()
+	if len(events) != 1 || len(events[0].Children) != 1 || events[0].Children[0].Children[0].Error != "timeout" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if s := root.String(); !strings.Contains(s, "Timeline:") || !strings.Contains(s, "    +") {
+		t.Errorf("unexpected timeline: %s", s)
+	}
+	if b, err := root.JSON(); err != nil || !strings.Contains(string(b), `"children"`) {
+		t.Errorf("unexpected json: %s, error: %v", b, err)
+	}
+}
+
+// recordLogger Record fields of the latest log entry
+type recordLogger struct {
+	levelLogger
+	msg    string
+	fields map[string]interface{}
+}
+
+func newRecordLogger() *recordLogger {
+	l := &recordLogger{}
+	l.levelLogger = levelLogger{level: LevelDebug, log: func(level Level, msg string, fields ...Field) {
+		l.msg = msg
+		l.fields = make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			l.fields[field.Key] = field.Value
+		}
+	}}
+	return l
+}
+
+func TestPageReader_PublishChildren(t *testing.T) {
+	logger := newRecordLogger()
+	pr := NewPageReader(10, logger)
+	notify := NewNotify("Open", "https://example.com")
+	navigate := notify.Child("Navigate", "https://example.com")
+	navigate.Child("RunTasks", "WaitReady").Finish(nil)
+	navigate.Finish(nil)
+	notify.Child("ParseHTML", "10 bytes").Finish(errors.New("empty"))
+	pr.publish(notify)
+
+	children, ok := logger.fields["children"].(fmt.Stringer)
+	if logger.msg != "Open" || !ok {
+		t.Fatalf("children spans should be logged: %v", logger.fields)
+	}
+	lines := strings.Split(children.String(), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "Navigate https://example.com") ||
+		!strings.HasPrefix(lines[1], "  +") || !strings.Contains(lines[1], "RunTasks WaitReady") ||
+		!strings.Contains(lines[2], "ParseHTML 10 bytes") || !strings.Contains(lines[2], "[ Error: empty ]") {
+		t.Errorf("unexpected timeline:\n%s", children)
+	}
+
+	var b strings.Builder
+	NewJSONLogger(&b, LevelInfo).Info("Open", notify.Fields()...)
+	var entry struct {
+		Children []NotifyEvent `json:"children"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.Children) != 2 || entry.Children[0].Function != "Navigate" || len(entry.Children[0].Children) != 1 || entry.Children[1].Error != "empty" {
+		t.Errorf("unexpected JSON children: %s", b.String())
+	}
+}
+
+// TestNotify_ConcurrentChildren Run with -race, listener goroutines add spans and logs while Open does
+func TestNotify_ConcurrentChildren(t *testing.T) {
+	notify := NewNotify("Open", "https://example.com")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				notify.Child("Console", fmt.Sprintf("%d-%d", i, j)).AddLog("message").Finish(nil)
+				notify.AddLogf("Log %d-%d", i, j)
+				_ = notify.Event()
+			}
+		}(i)
+	}
+	wg.Wait()
+	event := notify.Event()
+	if len(event.Children) != 200 || len(event.Logs) != 200 {
+		t.Errorf("unexpected children %d and logs %d", len(event.Children), len(event.Logs))
+	}
+	if !strings.Contains(notify.String(), "Console 9-19") {
+		t.Errorf("timeline should contain all children")
+	}
+}
@@ -2,13 +2,13 @@ package pagereader
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/chromedp/chromedp"
 	"log"
 	"os"
 	"strings"
 	"testing"
-	"time"
 )
 
 var pageReader *PageReader
@@ -16,14 +16,33 @@ var ctx context.Context
 var ctxCancelFunctions []context.CancelFunc
 
 func init() {
-	logger := log.New(os.Stdout, "", log.LstdFlags)
+	logger := NewStdLogger(log.New(os.Stdout, "", log.LstdFlags), LevelDebug)
 	pageReader = NewPageReader(40, logger)
 	pageReader.Debug = true
 	pageReader.ChromeDP.ExecAllocatorOptions = []chromedp.ExecAllocatorOption{
 		chromedp.Flag("headless", false),
 		chromedp.Flag("blink-settings", "imagesEnabled=false"),
 	}
-	ctx, ctxCancelFunctions = pageReader.ChromeDP.NewContext(30, *logger)
+	ctx, ctxCancelFunctions = pageReader.ChromeDP.NewContext(30, logger)
+}
+
+// errNoBrowser Error of noBrowserContext
+var errNoBrowser = errors.New("no browser in test")
+
+type noBrowserAllocator struct{}
+
+func (noBrowserAllocator) Allocate(ctx context.Context, opts ...chromedp.BrowserOption) (*chromedp.Browser, error) {
+	return nil, errNoBrowser
+}
+
+func (noBrowserAllocator) Wait() {}
+
+// noBrowserContext Chromedp context which fails to run with errNoBrowser, so tests never launch Chrome
+func noBrowserContext(t *testing.T) context.Context {
+	ctx, cancel := chromedp.NewContext(context.Background())
+	t.Cleanup(cancel)
+	chromedp.FromContext(ctx).Allocator = noBrowserAllocator{}
+	return ctx
 }
 
 func TestPageReader_PageSource(t *testing.T) {
@@ -64,8 +83,6 @@ func TestPageReader_Text(t *testing.T) {
 	}
 }
 
-}
-
 func TestPageReader_Refresh(t *testing.T) {
 	defer func() {
 		for _, cancelFunc := range ctxCancelFunctions {
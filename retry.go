@@ -1,25 +1,24 @@
 package pagereader
 
 import (
-	"log"
 	"time"
 )
 
 type RetryableFunc func() error
 
-func Retry(retryableFunc RetryableFunc, maxTimes int, logger *log.Logger) {
+func Retry(retryableFunc RetryableFunc, maxTimes int, logger Logger) {
 	if maxTimes <= 0 {
 		maxTimes = 1
 	}
 	currentTimes := 1
 	for {
-		logger.Printf("Retry %d time", currentTimes)
+		logger.Debug("Retry", F("times", currentTimes))
 		err := retryableFunc()
 		if err == nil || currentTimes > maxTimes {
 			break
 		}
 		if err != nil {
-			logger.Printf("Retry func execute error: %s", err.Error())
+			logger.Warn("Retry func execute failed", F("times", currentTimes), F("error", err))
 		}
 		time.Sleep(1 * time.Second)
 		currentTimes++
@@ -0,0 +1,423 @@
+package pagereader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type ExtractMode string
+
+const (
+	ModeText      ExtractMode = "text"       // Trimmed text, default mode
+	ModeAttr      ExtractMode = "attr"       // Trimmed attribute, implied if Rule.Attr is set
+	ModeHtml      ExtractMode = "html"       // Inner HTML
+	ModeOuterHtml ExtractMode = "outer_html" // Outer HTML
+	ModeExists    ExtractMode = "exists"     // Whether any selector matches, bool
+	ModeCount     ExtractMode = "count"      // Number of matched elements, int
+)
+
+// Schema Declarative extraction rules, keys are result field names
+type Schema map[string]*Rule
+
+// Selectors Selector fallback chain, a single selector string is accepted in YAML/JSON
+type Selectors []string
+
+// Rule Extraction rule of a field. A scalar rule in YAML/JSON is a shortcut of a single selector rule,
+// e.g. `title: "#productTitle"`, and a list is a shortcut of a selector chain.
+type Rule struct {
+	Selector Selectors   `json:"selector" yaml:"selector"`                     // Tried in order until one matches
+	Mode     ExtractMode `json:"mode,omitempty" yaml:"mode,omitempty"`         // Default is text, or attr if Attr is set
+	Attr     string      `json:"attr,omitempty" yaml:"attr,omitempty"`         // Attribute name of attr mode
+	Multiple bool        `json:"multiple,omitempty" yaml:"multiple,omitempty"` // Extract every matched element into a list
+	Items    Schema      `json:"items,omitempty" yaml:"items,omitempty"`       // Fields of matched element, selectors are scoped in the element
+	Process  []string    `json:"process,omitempty" yaml:"process,omitempty"`   // Post-processors applied in order, "name" or "name:arg"
+	Default  interface{} `json:"default,omitempty" yaml:"default,omitempty"`   // Value if nothing matched
+}
+
+func (s *Selectors) UnmarshalJSON(b []byte) error {
+	var selector string
+	if json.Unmarshal(b, &selector) == nil {
+		*s = Selectors{selector}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(s))
+}
+
+func (s *Selectors) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*s = Selectors{value.Value}
+		return nil
+	}
+	return value.Decode((*[]string)(s))
+}
+
+func (r *Rule) UnmarshalJSON(b []byte) error {
+	var selectors Selectors
+	if b = []byte(strings.TrimSpace(string(b))); len(b) > 0 && b[0] != '{' {
+		if err := json.Unmarshal(b, &selectors); err != nil {
+			return err
+		}
+		*r = Rule{Selector: selectors}
+		return nil
+	}
+	type rule Rule
+	return json.Unmarshal(b, (*rule)(r))
+}
+
+func (r *Rule) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		var selectors Selectors
+		if err := value.Decode(&selectors); err != nil {
+			return err
+		}
+		*r = Rule{Selector: selectors}
+		return nil
+	}
+	type rule Rule
+	return value.Decode((*rule)(r))
+}
+
+func (r Rule) mode() ExtractMode {
+	if r.Mode == "" {
+		if r.Attr != "" {
+			return ModeAttr
+		}
+		return ModeText
+	}
+	return r.Mode
+}
+
+// ParseSchema Parse YAML or JSON schema
+func ParseSchema(data []byte) (Schema, error) {
+	schema := Schema{}
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadSchema Parse YAML or JSON schema file
+func LoadSchema(filename string) (Schema, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(data)
+}
+
+// Validate Check selectors, modes and processors of every rule
+func (s Schema) Validate() error {
+	for _, name := range s.names() {
+		rule := s[name]
+		if rule == nil || len(rule.Selector) == 0 {
+			return fmt.Errorf("pagereader: schema field %s has no selector", name)
+		}
+		for _, selector := range rule.Selector {
+			if err := ValidateSelector(selector); err != nil {
+				return fmt.Errorf("pagereader: schema field %s has invalid selector %s: %w", name, selector, err)
+			}
+		}
+		switch mode := rule.mode(); mode {
+		case ModeText, ModeHtml, ModeOuterHtml, ModeExists, ModeCount:
+		case ModeAttr:
+			if rule.Attr == "" {
+				return fmt.Errorf("pagereader: schema field %s has no attr", name)
+			}
+		default:
+			return fmt.Errorf("pagereader: schema field %s has unknown mode %s", name, mode)
+		}
+		for _, process := range rule.Process {
+			processName, arg := splitProcess(process)
+			if processor(processName) == nil {
+				return fmt.Errorf("pagereader: schema field %s has unknown processor %s", name, processName)
+			}
+			if processName == "regex" {
+				if _, err := regexp.Compile(arg); err != nil {
+					return fmt.Errorf("pagereader: schema field %s has invalid regex: %w", name, err)
+				}
+			}
+		}
+		if err := rule.Items.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// names Sorted field names, keep logs and traces in stable order
+func (s Schema) names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Extract Extract fields of schema from current document
+func (pr PageReader) Extract(schema Schema) map[string]interface{} {
+	var root *goquery.Selection
+	if pr.Doc != nil {
+		root = pr.Doc.Selection
+	}
+	return pr.extractSchema(root, schema)
+}
+
+func (pr PageReader) extractSchema(root *goquery.Selection, schema Schema) map[string]interface{} {
+	values := make(map[string]interface{}, len(schema))
+	for _, name := range schema.names() {
+		rule := schema[name]
+		if rule == nil {
+			// Schemas built in code are not validated, see Schema.Validate
+			pr.Logger.Warn("Schema field has no rule", F("field", name))
+			values[name] = nil
+			continue
+		}
+		values[name] = pr.extractRule(root, name, rule)
+	}
+	return values
+}
+
+// matchRule Match selector chain of rule, elements are all elements matched by the matched selector
+func (pr PageReader) matchRule(root *goquery.Selection, name, field string, rule *Rule) (m Match, elements *goquery.Selection) {
+	mode := rule.mode()
+	m = pr.lookupIn(root, name, rule.Selector, func(s *goquery.Selection) ([]string, bool) {
+		elements = s
+		if !rule.Multiple {
+			s = s.First()
+		}
+		switch {
+		case rule.Items != nil || mode == ModeExists || mode == ModeCount:
+			return nil, true
+		case mode == ModeAttr && rule.Multiple:
+			values := make([]string, 0)
+			s.Each(func(i int, s *goquery.Selection) {
+				if value, exists := s.Attr(rule.Attr); exists {
+					values = append(values, strings.TrimSpace(value))
+				}
+			})
+			return values, len(values) > 0
+		case mode == ModeAttr:
+			return attrOf(rule.Attr)(s)
+		case mode == ModeText && !rule.Multiple:
+			return textOf(s)
+		}
+		values := s.Map(func(i int, s *goquery.Selection) (value string) {
+			switch mode {
+			case ModeHtml:
+				value, _ = s.Html()
+			case ModeOuterHtml:
+				value, _ = goquery.OuterHtml(s)
+			default:
+				value = s.Text()
+			}
+			return strings.TrimSpace(value)
+		})
+		return values, true
+	}, F("field", field))
+	return
+}
+
+func (pr PageReader) extractRule(root *goquery.Selection, name string, rule *Rule) interface{} {
+	mode := rule.mode()
+	m, elements := pr.matchRule(root, "Extract", name, rule)
+
+	switch {
+	case mode == ModeExists:
+		return m.Matched()
+	case mode == ModeCount:
+		return m.Count
+	case !m.Matched() && rule.Default != nil:
+		return rule.Default
+	case !m.Matched() && rule.Multiple:
+		return make([]interface{}, 0)
+	case !m.Matched():
+		return nil
+	case rule.Items != nil && rule.Multiple:
+		items := make([]interface{}, 0, elements.Length())
+		elements.Each(func(i int, s *goquery.Selection) {
+			items = append(items, pr.extractSchema(s, rule.Items))
+		})
+		return items
+	case rule.Items != nil:
+		return pr.extractSchema(elements.First(), rule.Items)
+	case rule.Multiple:
+		items := make([]interface{}, 0, len(m.Values))
+		for _, value := range m.Values {
+			items = append(items, pr.process(name, rule.Process, value))
+		}
+		return items
+	}
+	return pr.process(name, rule.Process, m.Value())
+}
+
+// Processor Post-processor of extracted value, arg is the part after ":" in rule, e.g. "regex:(\d+)"
+type Processor func(pr PageReader, value interface{}, arg string) (interface{}, error)
+
+var processors = struct {
+	sync.RWMutex
+	m map[string]Processor
+}{m: map[string]Processor{
+	"trim": stringProcessor(func(s, arg string) string {
+		if arg == "" {
+			return strings.TrimSpace(s)
+		}
+		return strings.Trim(s, arg)
+	}),
+	"lower":    stringProcessor(func(s, arg string) string { return strings.ToLower(s) }),
+	"upper":    stringProcessor(func(s, arg string) string { return strings.ToUpper(s) }),
+	"collapse": stringProcessor(func(s, arg string) string { return strings.Join(strings.Fields(s), " ") }),
+	"prefix":   stringProcessor(func(s, arg string) string { return arg + s }),
+	"suffix":   stringProcessor(func(s, arg string) string { return s + arg }),
+	"replace": stringProcessor(func(s, arg string) string {
+		// replace:old|new
+		parts := strings.SplitN(arg, "|", 2)
+		if len(parts) != 2 {
+			return s
+		}
+		return strings.ReplaceAll(s, parts[0], parts[1])
+	}),
+	"regex": func(pr PageReader, value interface{}, arg string) (interface{}, error) {
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, err
+		}
+		matches := re.FindStringSubmatch(toString(value))
+		switch {
+		case matches == nil:
+			return "", nil
+		case len(matches) > 1:
+			return matches[1], nil
+		}
+		return matches[0], nil
+	},
+	"url": func(pr PageReader, value interface{}, arg string) (interface{}, error) {
+		base, err := url.Parse(pr.URL)
+		if err != nil {
+			return nil, err
+		}
+		u, err := base.Parse(toString(value))
+		if err != nil {
+			return nil, err
+		}
+		return u.String(), nil
+	},
+	"int": func(pr PageReader, value interface{}, arg string) (interface{}, error) {
+		return strconv.Atoi(numeric(toString(value), false))
+	},
+	"float": func(pr PageReader, value interface{}, arg string) (interface{}, error) {
+		return strconv.ParseFloat(numeric(toString(value), true), 64)
+	},
+	"bool": func(pr PageReader, value interface{}, arg string) (interface{}, error) {
+		return parseBool(toString(value))
+	},
+}}
+
+// RegisterProcessor Add or replace a post-processor
+func RegisterProcessor(name string, p Processor) {
+	processors.Lock()
+	processors.m[name] = p
+	processors.Unlock()
+}
+
+func processor(name string) Processor {
+	processors.RLock()
+	defer processors.RUnlock()
+	return processors.m[name]
+}
+
+func splitProcess(process string) (name, arg string) {
+	parts := strings.SplitN(process, ":", 2)
+	name = strings.TrimSpace(parts[0])
+	if len(parts) == 2 {
+		arg = parts[1]
+	}
+	return
+}
+
+// process Apply processors in order, value is nil if any processor failed
+func (pr PageReader) process(name string, process []string, value string) interface{} {
+	var v interface{} = value
+	for _, p := range process {
+		processName, arg := splitProcess(p)
+		fn := processor(processName)
+		if fn == nil {
+			pr.Logger.Warn("Unknown processor", F("field", name), F("processor", processName))
+			return nil
+		}
+		var err error
+		if v, err = fn(pr, v, arg); err != nil {
+			pr.Logger.Warn("Process failed", F("field", name), F("processor", processName), F("value", value), F("error", err))
+			return nil
+		}
+	}
+	return v
+}
+
+func stringProcessor(fn func(s, arg string) string) Processor {
+	return func(pr PageReader, value interface{}, arg string) (interface{}, error) {
+		return fn(toString(value), arg), nil
+	}
+}
+
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// numeric The first number in s, e.g. "$1,299.00" => "1299.00". Minus sign is kept only if it's right before the first
+// digit, so "Price - $12" => "12". The number stops at the first other character, "," is allowed only between two digits
+// and "." only once and before a digit, so "$1,299.00 - $1,499.00" => "1299.00" and "3 of 5 stars" => "3".
+// Fraction is dropped if not decimal.
+func numeric(s string, decimal bool) string {
+	isDigit := func(i int) bool {
+		return i >= 0 && i < len(s) && s[i] >= '0' && s[i] <= '9'
+	}
+	sb := strings.Builder{}
+	digits, point := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isDigit(i):
+			sb.WriteByte(c)
+			digits = true
+		case !digits:
+			if c == '-' && isDigit(i+1) {
+				sb.WriteByte(c)
+			}
+		case c == ',' && isDigit(i-1) && isDigit(i+1):
+			// Thousands separator
+		case c == '.' && !point && isDigit(i+1) && decimal:
+			sb.WriteByte(c)
+			point = true
+		default:
+			return sb.String()
+		}
+	}
+	return sb.String()
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "", "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+	return false, errors.New("invalid bool value: " + s)
+}
@@ -0,0 +1,170 @@
+package pagereader
+
+import (
+	"reflect"
+	"testing"
+)
+
+const productSchema = `
+title: ["#productTitle", "h1"]
+brand:
+  selector: "#bylineInfo"
+  attr: href
+  process: [url]
+price:
+  selector: [".price", ".sale"]
+  process: ["float"]
+inStock:
+  selector: "#availability"
+  attr: data-available
+  process: [bool]
+sku:
+  selector: "#sku"
+  process: ["regex:SKU-(\\d+)", "int"]
+prime:
+  selector: "#prime"
+  mode: exists
+images:
+  selector: "#images img"
+  attr: src
+  multiple: true
+reviews:
+  selector: ".review"
+  multiple: true
+  items:
+    author: ".author"
+    rating:
+      selector: ".rating"
+      attr: data-rating
+      process: [int]
+    verified:
+      selector: ".verified"
+      mode: exists
+rank:
+  selector: "#rank"
+  default: 0
+`
+
+const productHtml = `<html><body>
+<h1> Coffee   Maker </h1>
+<a id="bylineInfo" href="/stores/acme">Acme</a>
+<span class="sale">$1,299.50</span>
+<div id="availability" data-available="Yes">In Stock.</div>
+<p id="sku">Item SKU-42 ready</p>
+<div id="images"><img src="/1.jpg"><img src="/2.jpg"><img></div>
+<div class="review"><span class="author">Tom</span><i class="rating" data-rating="5"></i><b class="verified"></b></div>
+<div class="review"><span class="author">Ann</span><i class="rating" data-rating="3.0"></i></div>
+</body></html>`
+
+func TestParseSchema(t *testing.T) {
+	schema, err := ParseSchema([]byte(productSchema))
+	if err != nil {
+		t.Fatalf("parse schema failed: %s", err.Error())
+	}
+	if !reflect.DeepEqual(schema["title"].Selector, Selectors{"#productTitle", "h1"}) {
+		t.Errorf("unexpected title selector: %v", schema["title"].Selector)
+	}
+	if schema["brand"].mode() != ModeAttr || schema["reviews"].Items["author"].Selector[0] != ".author" {
+		t.Errorf("unexpected schema: %+v", schema)
+	}
+
+	jsonSchema, err := ParseSchema([]byte(`{"title": "h1", "links": {"selector": ["a"], "attr": "href", "multiple": true}}`))
+	if err != nil {
+		t.Fatalf("parse JSON schema failed: %s", err.Error())
+	}
+	if jsonSchema["title"].Selector[0] != "h1" || !jsonSchema["links"].Multiple {
+		t.Errorf("unexpected schema: %+v", jsonSchema)
+	}
+
+	for _, s := range []string{`title: {attr: href}`, `title: {selector: h1, mode: unknown}`, `title: {selector: h1, process: [unknown]}`, `title: {selector: h1, mode: attr}`} {
+		if _, err = ParseSchema([]byte(s)); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+func TestPageReader_Extract(t *testing.T) {
+	schema, err := ParseSchema([]byte(productSchema))
+	if err != nil {
+		t.Fatalf("parse schema failed: %s", err.Error())
+	}
+	pr := NewPageReader(10, NopLogger())
+	pr.URL = "https://www.example.com/dp/1"
+	pr.SetHtml(productHtml)
+	values := pr.Extract(schema)
+	expected := map[string]interface{}{
+		"title":   "Coffee   Maker",
+		"brand":   "https://www.example.com/stores/acme",
+		"price":   1299.5,
+		"inStock": true,
+		"prime":   false,
+		"images":  []interface{}{"/1.jpg", "/2.jpg"},
+		"reviews": []interface{}{
+			map[string]interface{}{"author": "Tom", "rating": 5, "verified": true},
+			map[string]interface{}{"author": "Ann", "rating": 3, "verified": false},
+		},
+		"rank": 0,
+		"sku":  42,
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unexpected values:\n%#v\nexpected:\n%#v", values, expected)
+	}
+
+	values = pr.Extract(Schema{"title": nil, "items": {Selector: Selectors{".review"}, Multiple: true, Items: Schema{"author": nil}}})
+	if values["title"] != nil || !reflect.DeepEqual(values["items"], []interface{}{map[string]interface{}{"author": nil}, map[string]interface{}{"author": nil}}) {
+		t.Errorf("fields without rule should be nil, got %#v", values)
+	}
+
+	values = pr.Extract(Schema{"author": {Selector: Selectors{".author"}}})
+	if values["author"] != "Tom" {
+		t.Errorf("single text should be the first element's, got %v", values["author"])
+	}
+}
+
+func TestRegisterProcessor(t *testing.T) {
+	RegisterProcessor("reverse", stringProcessor(func(s, arg string) string {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	}))
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(productHtml)
+	values := pr.Extract(Schema{"brand": {Selector: Selectors{"#bylineInfo"}, Process: []string{"reverse", "upper"}}})
+	if values["brand"] != "EMCA" {
+		t.Errorf("unexpected value: %v", values["brand"])
+	}
+}
+
+func TestNumeric(t *testing.T) {
+	tests := []struct {
+		s       string
+		decimal bool
+		want    string
+	}{
+		{"$1,299.00", true, "1299.00"},
+		{"$1,299.00", false, "1299"},
+		{"-12.5 °C", true, "-12.5"},
+		{"Price - $12", true, "12"},
+		{"USD -$12", false, "12"},
+		{"2-3 days", false, "2"},
+		{"Rs. 1,299.00", true, "1299.00"},
+		{"Rs. 1,299.00", false, "1299"},
+		{"approx. 3.5", true, "3.5"},
+		{"approx. 3.5 kg.", true, "3.5"},
+		{"v1.2.3", true, "1.2"},
+		{". 5", true, "5"},
+		{"$1,299.00 - $1,499.00", true, "1299.00"},
+		{"$1,299 - $1,499", false, "1299"},
+		{"3 of 5 stars", false, "3"},
+		{"4.5 out of 5 stars", true, "4.5"},
+		{"1, 2, 3", false, "1"},
+		{"12.", true, "12"},
+	}
+	for _, test := range tests {
+		if v := numeric(test.s, test.decimal); v != test.want {
+			t.Errorf("numeric(%q, %v) = %q, want %q", test.s, test.decimal, v, test.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package pagereader
+
+import (
+	"bytes"
+	"context"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"image/jpeg"
+	"image/png"
+)
+
+type ScreenshotFormat string
+
+const (
+	ScreenshotPNG  ScreenshotFormat = "png"
+	ScreenshotJPEG ScreenshotFormat = "jpeg"
+)
+
+const defaultScreenshotQuality = 90
+
+type ScreenshotOptions struct {
+	Format   ScreenshotFormat // png(default) or jpeg
+	Quality  int              // jpeg compression quality [1..100], default 90
+	FullPage bool             // capture the full scrollable page instead of the viewport
+	Selector string           // capture a single element, take precedence over FullPage
+}
+
+func (o ScreenshotOptions) format() ScreenshotFormat {
+	if o.Format == ScreenshotJPEG {
+		return ScreenshotJPEG
+	}
+	return ScreenshotPNG
+}
+
+func (o ScreenshotOptions) quality() int {
+	if o.Quality <= 0 || o.Quality > 100 {
+		return defaultScreenshotQuality
+	}
+	return o.Quality
+}
+
+// Image Screenshot image data
+type Image struct {
+	Format ScreenshotFormat
+	Data   []byte
+}
+
+// screenshotTask Build screenshot action, image will be written to buf
+func screenshotTask(opts ScreenshotOptions, buf *[]byte) chromedp.Action {
+	format := opts.format()
+	quality := opts.quality()
+	switch {
+	case opts.Selector != "":
+		return chromedp.Tasks{
+			chromedp.Screenshot(opts.Selector, buf, chromedp.NodeVisible, chromedp.ByQuery),
+			convertScreenshot(format, quality, buf),
+		}
+	case opts.FullPage:
+		if format == ScreenshotPNG {
+			quality = 100 // chromedp.FullScreenshot capture png only if quality is 100
+		}
+		return chromedp.Tasks{
+			chromedp.FullScreenshot(buf, quality),
+			convertScreenshot(format, quality, buf),
+		}
+	default:
+		return chromedp.ActionFunc(func(ctx context.Context) (err error) {
+			params := page.CaptureScreenshot().WithFormat(page.CaptureScreenshotFormat(format))
+			if format == ScreenshotJPEG {
+				params = params.WithQuality(int64(quality))
+			}
+			*buf, err = params.Do(ctx)
+			return
+		})
+	}
+}
+
+// convertScreenshot Chrome only gives png for some screenshots, convert it to jpeg when need
+func convertScreenshot(format ScreenshotFormat, quality int, buf *[]byte) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if format != ScreenshotJPEG || !bytes.HasPrefix(*buf, []byte("\x89PNG")) {
+			return nil
+		}
+		img, err := png.Decode(bytes.NewReader(*buf))
+		if err != nil {
+			return err
+		}
+		var b bytes.Buffer
+		if err = jpeg.Encode(&b, img, &jpeg.Options{Quality: quality}); err != nil {
+			return err
+		}
+		*buf = b.Bytes()
+		return nil
+	}
+}
+
+// Screenshot Capture current page, capture viewport by default, see ScreenshotOptions
+func (pr *PageReader) Screenshot(ctx context.Context, opts ScreenshotOptions) ([]byte, error) {
+	var buf []byte
+	err := pr.RunTasks(ctx, "Screenshot", pr.Config.Timeout, chromedp.Tasks{screenshotTask(opts, &buf)})
+	return buf, err
+}
+
+// FullScreenshot Capture the full scrollable page
+func (pr *PageReader) FullScreenshot(ctx context.Context, opts ScreenshotOptions) ([]byte, error) {
+	opts.FullPage = true
+	opts.Selector = ""
+	return pr.Screenshot(ctx, opts)
+}
+
+// ElementScreenshot Capture the first element matched by selector
+func (pr *PageReader) ElementScreenshot(ctx context.Context, selector string, opts ScreenshotOptions) ([]byte, error) {
+	opts.Selector = selector
+	return pr.Screenshot(ctx, opts)
+}
+
+// SetScreenshotOnOpen Open will take screenshot and attach it to PageReader.Image, nil to disable
+func (pr *PageReader) SetScreenshotOnOpen(opts *ScreenshotOptions) *PageReader {
+	pr.Config.ScreenshotOnOpen = opts
+	return pr
+}
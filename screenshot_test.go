@@ -0,0 +1,40 @@
+package pagereader
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestConvertScreenshot(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := b.Bytes()
+	if err := convertScreenshot(ScreenshotPNG, 90, &buf)(context.Background()); err != nil || !bytes.Equal(buf, b.Bytes()) {
+		t.Errorf("png should not be converted, error: %v", err)
+	}
+	if err := convertScreenshot(ScreenshotJPEG, 90, &buf)(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf, []byte{0xff, 0xd8}) {
+		t.Errorf("expected jpeg data")
+	}
+}
+
+func TestPageReader_SetScreenshotOnOpen(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	opts := &ScreenshotOptions{FullPage: true}
+	pr.SetScreenshotOnOpen(opts)
+	// Config fields are promoted, they must not be shadowed by methods of PageReader
+	if pr.ScreenshotOnOpen != opts {
+		t.Errorf("unexpected screenshot options: %+v", pr.ScreenshotOnOpen)
+	}
+}
@@ -0,0 +1,132 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Script JavaScript source which will be evaluated on every new document before page scripts
+type Script struct {
+	Name   string
+	Source string
+}
+
+// AddScript Add a script from string, it's useful for go:embed strings
+func (c *ChromeDP) AddScript(name, source string) *ChromeDP {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return c
+	}
+	if name == "" {
+		name = "script"
+	}
+	c.scripts = append(c.scripts, Script{Name: name, Source: source})
+	return c
+}
+
+// AddScriptFile Add a script from local file
+func (c *ChromeDP) AddScriptFile(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	c.AddScript(filepath.Base(filename), string(b))
+	return nil
+}
+
+// AddScriptFS Add a script from file system, e.g. embed.FS
+func (c *ChromeDP) AddScriptFS(fsys fs.FS, name string) error {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	c.AddScript(name, string(b))
+	return nil
+}
+
+func (c *ChromeDP) Scripts() []Script {
+	return c.scripts
+}
+
+// targetScripts Scripts registered on a target, the lock is held while registering
+type targetScripts struct {
+	sync.Mutex
+	count int
+}
+
+// InjectScripts Register scripts through Page.addScriptToEvaluateOnNewDocument
+// Every script only register once on a target, so call it before every navigate is safe.
+func (c *ChromeDP) InjectScripts() chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(c.scripts) == 0 {
+			return nil
+		}
+		cc := chromedp.FromContext(ctx)
+		if cc == nil || cc.Target == nil {
+			return errors.New("pagereader: no target in context")
+		}
+		ts := c.targetScripts(ctx, cc.Target.TargetID)
+		// Parallel callers on the same target wait until scripts are registered, before they navigate
+		ts.Lock()
+		defer ts.Unlock()
+		for ts.count < len(c.scripts) {
+			if _, err := page.AddScriptToEvaluateOnNewDocument(c.scripts[ts.count].Source).Do(ctx); err != nil {
+				// Scripts from the failed one will be registered next time
+				return err
+			}
+			ts.count++
+		}
+		return nil
+	}
+}
+
+// targetScripts Registration state of target, it's removed when the target is destroyed
+func (c *ChromeDP) targetScripts(ctx context.Context, id target.ID) *targetScripts {
+	c.injectedScriptsMu.Lock()
+	defer c.injectedScriptsMu.Unlock()
+	if ts, ok := c.injectedScripts[id]; ok {
+		return ts
+	}
+	if c.injectedScripts == nil {
+		c.injectedScripts = make(map[target.ID]*targetScripts)
+	}
+	ts := &targetScripts{}
+	c.injectedScripts[id] = ts
+	// Action context ends with the action, the listener lives until the target is destroyed
+	lctx, cancel := context.WithCancel(detachedContext{ctx})
+	chromedp.ListenBrowser(lctx, func(ev interface{}) {
+		if e, ok := ev.(*target.EventTargetDestroyed); ok && e.TargetID == id {
+			c.injectedScriptsMu.Lock()
+			delete(c.injectedScripts, id)
+			c.injectedScriptsMu.Unlock()
+			cancel()
+		}
+	})
+	return ts
+}
+
+// detachedContext Values of context without its deadline and cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
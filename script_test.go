@@ -0,0 +1,73 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"github.com/mailru/easyjson"
+	"sync"
+	"testing"
+)
+
+// scriptExecutor Record scripts registered by Page.addScriptToEvaluateOnNewDocument without a browser
+type scriptExecutor struct {
+	sync.Mutex
+	calls int
+	fail  int // Fail the nth call if not 0
+}
+
+func (e *scriptExecutor) Execute(ctx context.Context, method string, params easyjson.Marshaler, res easyjson.Unmarshaler) error {
+	e.Lock()
+	defer e.Unlock()
+	e.calls++
+	if e.calls == e.fail {
+		return errors.New("register failed")
+	}
+	return nil
+}
+
+func TestChromeDP_InjectScriptsParallel(t *testing.T) {
+	c := (&ChromeDP{}).AddScript("a", "window.a = 1").AddScript("b", "window.b = 2")
+	executor := &scriptExecutor{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		ctx, cancel := chromedp.NewContext(context.Background())
+		defer cancel()
+		chromedp.FromContext(ctx).Target = &chromedp.Target{TargetID: target.ID(fmt.Sprintf("tab-%d", i%4))}
+		ctx = cdp.WithExecutor(ctx, executor)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.InjectScripts().Do(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// 4 targets, every script is registered once per target
+	if executor.calls != 8 {
+		t.Errorf("expected 8 registrations, got %d", executor.calls)
+	}
+}
+
+func TestChromeDP_InjectScriptsRetry(t *testing.T) {
+	c := (&ChromeDP{}).AddScript("a", "window.a = 1").AddScript("b", "window.b = 2")
+	executor := &scriptExecutor{fail: 2}
+	ctx, cancel := chromedp.NewContext(context.Background())
+	defer cancel()
+	chromedp.FromContext(ctx).Target = &chromedp.Target{TargetID: "tab"}
+	ctx = cdp.WithExecutor(ctx, executor)
+	if err := c.InjectScripts().Do(ctx); err == nil {
+		t.Fatal("expected register error")
+	}
+	if err := c.InjectScripts().Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// a, b failed, then b again
+	if executor.calls != 3 {
+		t.Errorf("expected 3 registrations, got %d", executor.calls)
+	}
+}
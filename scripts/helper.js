@@ -0,0 +1,105 @@
+/*! Page Reader in-page helper */
+(function (w) {
+    "use strict";
+    if (w.__pageReader) {
+        return;
+    }
+
+    function query(selector, root) {
+        if (typeof selector !== "string") {
+            return selector ? [selector] : [];
+        }
+        return Array.prototype.slice.call((root || document).querySelectorAll(selector));
+    }
+
+    function first(selector, root) {
+        const elements = query(selector, root);
+        return elements.length ? elements[0] : null;
+    }
+
+    function text(selector, root) {
+        const el = first(selector, root);
+        return el ? (el.textContent || "").trim() : "";
+    }
+
+    function attr(selector, name, root) {
+        const el = first(selector, root);
+        if (!el || !el.hasAttribute(name)) {
+            return null;
+        }
+        return (el.getAttribute(name) || "").trim();
+    }
+
+    // scroll(selector) scroll element into view, scroll(x, y) scroll window to position,
+    // scroll() scroll to the bottom of page
+    function scroll(selector, y) {
+        if (typeof selector === "number") {
+            w.scrollTo(selector, y || 0);
+            return true;
+        }
+        if (selector === undefined || selector === null) {
+            w.scrollTo(0, document.documentElement.scrollHeight || document.body.scrollHeight);
+            return true;
+        }
+        const el = first(selector);
+        if (!el) {
+            return false;
+        }
+        el.scrollIntoView({block: "center", inline: "nearest"});
+        return true;
+    }
+
+    function click(selector) {
+        const el = first(selector);
+        if (!el) {
+            return false;
+        }
+        el.click();
+        return true;
+    }
+
+    function sleep(ms) {
+        return new Promise(function (resolve) {
+            setTimeout(resolve, ms);
+        });
+    }
+
+    // wait resolve true when selector matched before timeout(milliseconds), otherwise false
+    function wait(selector, timeout) {
+        timeout = timeout || 10000;
+        return new Promise(function (resolve) {
+            if (first(selector)) {
+                resolve(true);
+                return;
+            }
+            const observer = new MutationObserver(function () {
+                if (first(selector)) {
+                    observer.disconnect();
+                    clearTimeout(timer);
+                    resolve(true);
+                }
+            });
+            const timer = setTimeout(function () {
+                observer.disconnect();
+                resolve(!!first(selector));
+            }, timeout);
+            observer.observe(document.documentElement || document, {childList: true, subtree: true, attributes: true});
+        });
+    }
+
+    Object.defineProperty(w, "__pageReader", {
+        value: Object.freeze({
+            query: query,
+            first: first,
+            text: text,
+            attr: attr,
+            scroll: scroll,
+            click: click,
+            sleep: sleep,
+            wait: wait
+        }),
+        enumerable: false,
+        configurable: false,
+        writable: false
+    });
+})(window);
@@ -0,0 +1,146 @@
+/*! Page Reader live DOM query, called with (selector, mode, name) */
+(function (selector, mode, name) {
+    "use strict";
+    var nodes = [];
+    if (selector.indexOf("xpath:") === 0) {
+        var result = document.evaluate(selector.slice(6), document, null, XPathResult.ANY_TYPE, null);
+        switch (result.resultType) {
+            case XPathResult.STRING_TYPE:
+                return {count: result.stringValue ? 1 : 0, values: [result.stringValue.trim()], matched: !!result.stringValue.trim()};
+            case XPathResult.NUMBER_TYPE:
+                return {count: 1, values: [String(result.numberValue)], matched: true};
+            case XPathResult.BOOLEAN_TYPE:
+                return {count: result.booleanValue ? 1 : 0, values: [String(result.booleanValue)], matched: result.booleanValue};
+        }
+        for (var node = result.iterateNext(); node; node = result.iterateNext()) {
+            nodes.push(node);
+        }
+    } else if (selector === "") {
+        nodes = [document.documentElement];
+    } else {
+        nodes = Array.prototype.slice.call(document.querySelectorAll(selector));
+    }
+
+    function text(node) {
+        return (node.textContent || "").trim();
+    }
+
+    function attr(node) {
+        if (!node.hasAttribute || !node.hasAttribute(name)) {
+            return null;
+        }
+        return (node.getAttribute(name) || "").trim();
+    }
+
+    function property(node) {
+        var value = node[name];
+        if (value === undefined || value === null) {
+            return null;
+        }
+        return typeof value === "object" ? JSON.stringify(value) : String(value);
+    }
+
+    var skipTags = {SCRIPT: 1, STYLE: 1, TEMPLATE: 1, NOSCRIPT: 1, HEAD: 1, TITLE: 1, META: 1, LINK: 1};
+    var blockDisplay = /^(block|flex|grid|list-item|table|table-row|table-caption|table-row-group|table-header-group|table-footer-group|flow-root)$/;
+
+    // visuallyHidden Screen-reader-only, transparent or off-screen element
+    function visuallyHidden(el, style) {
+        if (parseFloat(style.opacity) === 0) {
+            return true;
+        }
+        if (style.clip === "rect(0px, 0px, 0px, 0px)" || /^inset\((50|100)%\)$/.test(style.clipPath)) {
+            return true;
+        }
+        if (style.display === "contents") {
+            return false;
+        }
+        var rect = el.getBoundingClientRect();
+        if (el.getClientRects().length === 0) {
+            return true;
+        }
+        if (rect.width <= 1 && rect.height <= 1 && style.overflow === "hidden") {
+            return true;
+        }
+        return rect.right + window.scrollX <= 0 || rect.bottom + window.scrollY <= 0;
+    }
+
+    function collect(node, out, visible, pre) {
+        if (node.nodeType === 3) {
+            if (visible) {
+                out.push(pre ? node.data : node.data.replace(/[ \t\r\n\f]+/g, " "));
+            }
+            return;
+        }
+        if (node.nodeType !== 1 || skipTags[node.tagName]) {
+            return;
+        }
+        var style = window.getComputedStyle(node);
+        if (style.display === "none" || visuallyHidden(node, style)) {
+            return;
+        }
+        if (node.tagName === "BR") {
+            out.push("\n");
+            return;
+        }
+        var block = blockDisplay.test(style.display);
+        if (block) {
+            out.push("\n");
+        } else if (style.display === "table-cell" && node.previousElementSibling) {
+            out.push("\t");
+        }
+        for (var child = node.firstChild; child; child = child.nextSibling) {
+            collect(child, out, style.visibility === "visible", /^pre/.test(style.whiteSpace));
+        }
+        if (block) {
+            out.push("\n");
+        }
+    }
+
+    // visibleText Rendered text with line breaks between block elements
+    function visibleText(node) {
+        if (node.nodeType !== 1) {
+            return text(node);
+        }
+        var out = [];
+        collect(node, out, true, false);
+        return out.join("").split("\n").map(function (line) {
+            return line.replace(/ {2,}/g, " ").trim();
+        }).filter(function (line) {
+            return line !== "";
+        }).join("\n");
+    }
+
+    var values = [];
+    var matched = nodes.length > 0;
+    switch (mode) {
+        case "text":
+            values = [nodes.map(text).join("").trim()];
+            matched = values[0] !== "";
+            break;
+        case "text_all":
+            values = nodes.map(text);
+            break;
+        case "visible_text":
+            values = [nodes.map(visibleText).filter(function (v) {
+                return v !== "";
+            }).join("\n")];
+            matched = values[0] !== "";
+            break;
+        case "visible_text_all":
+            values = nodes.map(visibleText);
+            break;
+        case "attr":
+        case "property":
+            var value = matched ? (mode === "attr" ? attr(nodes[0]) : property(nodes[0])) : null;
+            matched = value !== null;
+            values = matched ? [value] : [];
+            break;
+        case "attr_all":
+            values = nodes.map(attr).filter(function (v) {
+                return v !== null;
+            });
+            matched = values.length > 0;
+            break;
+    }
+    return {count: nodes.length, values: values, matched: matched};
+})
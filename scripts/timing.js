@@ -0,0 +1,72 @@
+/*! Page Reader navigation timing and web vitals */
+new Promise(function (resolve) {
+    "use strict";
+    const result = {
+        redirect: 0, dns: 0, connect: 0, tls: 0, ttfb: 0, response: 0,
+        domInteractive: 0, domContentLoaded: 0, load: 0,
+        transferSize: 0, encodedBodySize: 0, decodedBodySize: 0,
+        resourceCount: 0, resourceTransferSize: 0,
+        fcp: 0, lcp: 0, cls: 0
+    };
+    const nav = performance.getEntriesByType ? performance.getEntriesByType("navigation")[0] : null;
+    if (nav) {
+        result.redirect = nav.redirectEnd - nav.redirectStart;
+        result.dns = nav.domainLookupEnd - nav.domainLookupStart;
+        result.connect = nav.connectEnd - nav.connectStart;
+        result.tls = nav.secureConnectionStart > 0 ? nav.connectEnd - nav.secureConnectionStart : 0;
+        result.ttfb = nav.responseStart - nav.startTime;
+        result.response = nav.responseEnd - nav.responseStart;
+        result.domInteractive = nav.domInteractive;
+        result.domContentLoaded = nav.domContentLoadedEventEnd;
+        result.load = nav.loadEventEnd;
+        result.transferSize = nav.transferSize || 0;
+        result.encodedBodySize = nav.encodedBodySize || 0;
+        result.decodedBodySize = nav.decodedBodySize || 0;
+    } else if (performance.timing) {
+        // Legacy performance.timing, values are epoch milliseconds
+        const t = performance.timing;
+        const start = t.navigationStart;
+        result.redirect = t.redirectEnd - t.redirectStart;
+        result.dns = t.domainLookupEnd - t.domainLookupStart;
+        result.connect = t.connectEnd - t.connectStart;
+        result.tls = t.secureConnectionStart > 0 ? t.connectEnd - t.secureConnectionStart : 0;
+        result.ttfb = t.responseStart - start;
+        result.response = t.responseEnd - t.responseStart;
+        result.domInteractive = t.domInteractive > 0 ? t.domInteractive - start : 0;
+        result.domContentLoaded = t.domContentLoadedEventEnd > 0 ? t.domContentLoadedEventEnd - start : 0;
+        result.load = t.loadEventEnd > 0 ? t.loadEventEnd - start : 0;
+    }
+
+    const resources = performance.getEntriesByType ? performance.getEntriesByType("resource") : [];
+    result.resourceCount = resources.length;
+    resources.forEach(function (r) {
+        result.resourceTransferSize += r.transferSize || 0;
+    });
+
+    const observe = function (type, callback) {
+        try {
+            new PerformanceObserver(function (list) {
+                list.getEntries().forEach(callback);
+            }).observe({type: type, buffered: true});
+        } catch (e) {
+            // Entry type is not supported
+        }
+    };
+    observe("paint", function (e) {
+        if (e.name === "first-contentful-paint") {
+            result.fcp = e.startTime;
+        }
+    });
+    observe("largest-contentful-paint", function (e) {
+        result.lcp = Math.max(result.lcp, e.renderTime || e.loadTime || e.startTime);
+    });
+    observe("layout-shift", function (e) {
+        if (!e.hadRecentInput) {
+            result.cls += e.value;
+        }
+    });
+    // Buffered entries are delivered asynchronously
+    setTimeout(function () {
+        resolve(result);
+    }, 50);
+});
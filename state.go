@@ -0,0 +1,366 @@
+package pagereader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/chromedp"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	StateSourceScript     = "script"     // <script type="application/json">, e.g. __NEXT_DATA__
+	StateSourceAssignment = "assignment" // Inline script assignment, e.g. window.__INITIAL_STATE__ = {...}
+	StateSourceLive       = "live"       // Evaluated in the live page
+)
+
+// State JSON state object embedded in page, e.g. __NEXT_DATA__, window.__INITIAL_STATE__ or var data = {...}
+type State struct {
+	Name   string      `json:"name"` // Script id or assigned variable name without "window."
+	Source string      `json:"source"`
+	Data   interface{} `json:"data"`
+}
+
+// Query Query data by JSONPath, see QueryJSON
+func (s State) Query(path string) ([]interface{}, error) {
+	return QueryJSON(s.Data, path)
+}
+
+// Get The first value of JSONPath, nil if not found or path is invalid
+func (s State) Get(path string) interface{} {
+	if values, err := s.Query(path); err == nil && len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
+
+// stateAssignment Start of assignment in inline script, e.g. window.__INITIAL_STATE__ =, window["data"] =, var data =
+var stateAssignment = regexp.MustCompile(`(?:\b(?:window|self|globalThis)\s*(?:\.\s*([A-Za-z_$][\w$]*)|\[\s*["']([^"']+)["']\s*\])|\b(?:var|let|const)\s+([A-Za-z_$][\w$]*))\s*=\s*`)
+
+// States Parse all JSON state objects in Doc, blobs which can't be parsed are skipped
+func (pr PageReader) States() []State {
+	states := make([]State, 0)
+	if pr.Doc == nil {
+		return states
+	}
+	finish := pr.startExtraction(pr.extractionContext(), "States")
+	pr.Doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		if src, ok := s.Attr("src"); ok && src != "" {
+			return
+		}
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			return
+		}
+		typ := strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))
+		switch {
+		case typ == "application/json" || strings.HasSuffix(typ, "+json") && typ != "application/ld+json":
+			var data interface{}
+			if err := json.Unmarshal([]byte(text), &data); err != nil {
+				pr.debug("Parse JSON script failed", F("id", s.AttrOr("id", "")), F("error", err))
+				return
+			}
+			states = append(states, State{Name: s.AttrOr("id", ""), Source: StateSourceScript, Data: data})
+		case typ == "" || strings.Contains(typ, "javascript") || typ == "module":
+			states = append(states, pr.parseAssignments(text)...)
+		}
+	})
+	finish(len(states) > 0, 0, nil)
+	return states
+}
+
+// State The first state object of name, e.g. __NEXT_DATA__, __INITIAL_STATE__, data. nil if not found
+func (pr PageReader) State(name string) *State {
+	name = strings.TrimPrefix(name, "window.")
+	for _, state := range pr.States() {
+		if state.Name == name {
+			return &state
+		}
+	}
+	pr.debug("State not found", F("name", name))
+	return nil
+}
+
+func (pr PageReader) parseAssignments(script string) []State {
+	states := make([]State, 0)
+	for _, loc := range stateAssignment.FindAllStringSubmatchIndex(script, -1) {
+		name := ""
+		for i := 2; i < len(loc); i += 2 {
+			if loc[i] >= 0 {
+				name = script[loc[i]:loc[i+1]]
+				break
+			}
+		}
+		literal, err := assignedJSON(script[loc[1]:])
+		if err == nil {
+			var data interface{}
+			if err = json.Unmarshal([]byte(literal), &data); err == nil {
+				states = append(states, State{Name: name, Source: StateSourceAssignment, Data: data})
+				continue
+			}
+		}
+		if literal != "" {
+			pr.debug("Parse state assignment failed", F("name", name), F("error", err))
+		}
+	}
+	return states
+}
+
+// assignedJSON JSON of right-hand side, supports object or array literal and JSON.parse("...")
+func assignedJSON(rhs string) (string, error) {
+	if strings.HasPrefix(rhs, "JSON.parse(") {
+		arg := strings.TrimSpace(rhs[len("JSON.parse("):])
+		if arg == "" || (arg[0] != '"' && arg[0] != '\'' && arg[0] != '`') {
+			return "", errors.New("unsupported JSON.parse argument")
+		}
+		s, _, err := readJSString(arg, 0)
+		return s, err
+	}
+	if rhs == "" || (rhs[0] != '{' && rhs[0] != '[') {
+		return "", nil
+	}
+	end, err := literalEnd(rhs)
+	if err != nil {
+		return "", err
+	}
+	return jsToJSON(rhs[:end])
+}
+
+// literalEnd End index of balanced object or array literal at start of src
+func literalEnd(src string) (int, error) {
+	depth := 0
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '"', '\'', '`':
+			_, end, err := readJSString(src, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end - 1
+		case '/':
+			// Comments can contain quotes and brackets, e.g. // don't {
+			if i+1 < len(src) && src[i+1] == '/' {
+				if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+					i += end
+				} else {
+					i = len(src)
+				}
+			} else if i+1 < len(src) && src[i+1] == '*' {
+				end := strings.Index(src[i+2:], "*/")
+				if end < 0 {
+					return 0, errors.New("unterminated comment")
+				}
+				i += end + 3
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, errors.New("unterminated literal")
+}
+
+// jsToJSON Convert JavaScript object literal to JSON: quote keys, convert single-quoted strings,
+// remove comments and trailing commas, undefined, NaN and Infinity are converted to null, !0 and !1 to true and false
+func jsToJSON(src string) (string, error) {
+	out := make([]byte, 0, len(src))
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			s, end, err := readJSString(src, i)
+			if err != nil {
+				return "", err
+			}
+			b, _ := json.Marshal(s)
+			out = append(out, b...)
+			i = end
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return "", errors.New("unterminated comment")
+			}
+			i += end + 4
+		case c == '}' || c == ']':
+			out = bytes.TrimRight(out, " \t\r\n")
+			out = bytes.TrimSuffix(out, []byte(","))
+			out = append(out, c)
+			i++
+		case c == '!' && i+1 < len(src) && (src[i+1] == '0' || src[i+1] == '1'):
+			out = append(out, map[byte]string{'0': "true", '1': "false"}[src[i+1]]...)
+			i += 2
+		case c >= '0' && c <= '9' || c == '.':
+			j := i + 1
+			for j < len(src) && (isIdentPart(src[j]) || src[j] == '.' || (src[j] == '+' || src[j] == '-') && (src[j-1] == 'e' || src[j-1] == 'E')) {
+				j++
+			}
+			word := src[i:j]
+			switch {
+			case isKey(src, j):
+				out = append(out, strconv.Quote(word)...)
+			case c == '.':
+				out = append(out, "0"+word...)
+			default:
+				out = append(out, word...)
+			}
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(src) && isIdentPart(src[j]) {
+				j++
+			}
+			word := src[i:j]
+			switch {
+			case isKey(src, j):
+				out = append(out, strconv.Quote(word)...)
+			case word == "true" || word == "false" || word == "null":
+				out = append(out, word...)
+			case word == "undefined" || word == "NaN" || word == "Infinity":
+				out = append(out, "null"...)
+			default:
+				return "", fmt.Errorf("unsupported expression %s", word)
+			}
+			i = j
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+	return string(out), nil
+}
+
+// isKey Whether a colon follows position i after spaces
+func isKey(src string, i int) bool {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\r' || src[i] == '\n') {
+		i++
+	}
+	return i < len(src) && src[i] == ':'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// readJSString Decode JavaScript string literal at src[start], return decoded value and end index
+func readJSString(src string, start int) (string, int, error) {
+	quote := src[start]
+	sb := strings.Builder{}
+	for i := start + 1; i < len(src); {
+		c := src[i]
+		switch {
+		case c == quote:
+			return sb.String(), i + 1, nil
+		case c == '$' && quote == '`' && i+1 < len(src) && src[i+1] == '{':
+			return "", 0, errors.New("template literal with expressions")
+		case c == '\\' && i+1 < len(src):
+			e := src[i+1]
+			i += 2
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'v':
+				sb.WriteByte('\v')
+			case '0':
+				sb.WriteByte(0)
+			case '\n':
+				// Line continuation
+			case 'x', 'u':
+				size := 2
+				if e == 'u' {
+					size = 4
+					if i < len(src) && src[i] == '{' {
+						end := strings.IndexByte(src[i:], '}')
+						if end < 0 {
+							return "", 0, errors.New("invalid unicode escape")
+						}
+						size = end + 1
+					}
+				}
+				if i+size > len(src) {
+					return "", 0, errors.New("invalid escape")
+				}
+				n, err := strconv.ParseUint(strings.Trim(src[i:i+size], "{}"), 16, 32)
+				if err != nil {
+					return "", 0, err
+				}
+				r := rune(n)
+				// Surrogate pair of \uXXXX\uXXXX
+				if r >= 0xD800 && r < 0xDC00 && i+size+6 <= len(src) && src[i+size] == '\\' && src[i+size+1] == 'u' {
+					if low, err := strconv.ParseUint(src[i+size+2:i+size+6], 16, 32); err == nil && low >= 0xDC00 && low < 0xE000 {
+						r = (r-0xD800)<<10 + (rune(low) - 0xDC00) + 0x10000
+						i += 6
+					}
+				}
+				sb.WriteRune(r)
+				i += size
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(src[i:])
+			sb.WriteRune(r)
+			i += size
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+// LiveState Evaluate expression in the live page and parse the result as JSON state, e.g. "window.__NUXT__",
+// circular references and functions are dropped
+func (pr PageReader) LiveState(ctx context.Context, expression string) (*State, error) {
+	var raw string
+	script := fmt.Sprintf(`(function () {
+    // Objects being serialized from the root to the holder, only references back to them are circular
+    var ancestors = [];
+    var s = JSON.stringify((%s), function (k, v) {
+        if (typeof v === "object" && v !== null) {
+            while (ancestors.length > 0 && ancestors[ancestors.length - 1] !== this) {
+                ancestors.pop();
+            }
+            if (ancestors.indexOf(v) >= 0) {
+                return undefined;
+            }
+            ancestors.push(v);
+        }
+        return v;
+    });
+    return s === undefined ? "null" : s;
+})()`, expression)
+	if err := pr.RunTasks(ctx, "LiveState", pr.Config.Timeout, chromedp.Tasks{chromedp.Evaluate(script, &raw)}); err != nil {
+		return nil, err
+	}
+	state := &State{Name: strings.TrimPrefix(expression, "window."), Source: StateSourceLive}
+	if err := json.Unmarshal([]byte(raw), &state.Data); err != nil {
+		return nil, err
+	}
+	if state.Data == nil {
+		return nil, fmt.Errorf("pagereader: state %s not found", expression)
+	}
+	return state, nil
+}
@@ -0,0 +1,127 @@
+package pagereader
+
+import (
+	"reflect"
+	"testing"
+)
+
+const stateHtml = `<html><head>
+<script id="__NEXT_DATA__" type="application/json">{"props": {"pageProps": {"product": {"id": 7, "title": "Kettle"}}}}</script>
+<script type="application/ld+json">{"@type": "Product"}</script>
+<script src="/app.js"></script>
+<script>
+  // Initial state
+  window.__INITIAL_STATE__ = {
+    // don't close } here
+    user: {name: 'Jane', "vip": !0, age: undefined}, /* ] */
+    items: [
+      {sku: 'A1', price: 1.5e2, tags: ['new', "hot"],},
+      {sku: 'B2', price: .5, /* sale */ tags: []},
+    ],
+    note: "say \"hi\"!",
+  };
+  window["config"] = JSON.parse('{"locale":"en-US","debug":false}');
+  var data = [1, 2, 3];
+  var handler = function () {};
+</script>
+</head><body></body></html>`
+
+func TestPageReader_States(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(stateHtml)
+	states := pr.States()
+	names := make([]string, len(states))
+	for i, s := range states {
+		names[i] = s.Name
+	}
+	if want := []string{"__NEXT_DATA__", "__INITIAL_STATE__", "config", "data"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("states = %v, want %v", names, want)
+	}
+
+	next := pr.State("__NEXT_DATA__")
+	if next == nil || next.Source != StateSourceScript || next.Get("props.pageProps.product.title") != "Kettle" {
+		t.Errorf("unexpected __NEXT_DATA__: %+v", next)
+	}
+
+	state := pr.State("window.__INITIAL_STATE__")
+	if state == nil || state.Source != StateSourceAssignment {
+		t.Fatalf("unexpected __INITIAL_STATE__: %+v", state)
+	}
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"$.user.name", "Jane"},
+		{"$.user.vip", true},
+		{"$.user.age", nil},
+		{"$.items[0].price", 150.0},
+		{"$.items[-1].price", 0.5},
+		{"$.items[1].tags", []interface{}{}},
+		{"$.note", `say "hi"!`},
+	}
+	for _, test := range tests {
+		if v := state.Get(test.path); !reflect.DeepEqual(v, test.want) {
+			t.Errorf("Get(%s) = %#v, want %#v", test.path, v, test.want)
+		}
+	}
+
+	if config := pr.State("config"); config == nil || config.Get("locale") != "en-US" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+	if pr.State("handler") != nil {
+		t.Errorf("function assignment should be skipped")
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	var data interface{} = map[string]interface{}{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "A", "price": 8.0, "isbn": "1", "tags": []interface{}{"x"}},
+				map[string]interface{}{"title": "B", "price": 12.0, "note": "a<b"},
+				map[string]interface{}{"title": "C", "price": 22.0, "isbn": "3"},
+			},
+			"bicycle": map[string]interface{}{"title": "Bike", "price": 19.0},
+		},
+	}
+	tests := []struct {
+		path string
+		want []interface{}
+	}{
+		{"$.store.book[0].title", []interface{}{"A"}},
+		{"store['book'][-1]['title']", []interface{}{"C"}},
+		{"$.store.book[*].title", []interface{}{"A", "B", "C"}},
+		{"$.store.book[0,2].title", []interface{}{"A", "C"}},
+		{"$.store.book[1:].title", []interface{}{"B", "C"}},
+		{"$..price", []interface{}{19.0, 8.0, 12.0, 22.0}},
+		{"$.store.book[?(@.isbn)].title", []interface{}{"A", "C"}},
+		{"$.store.book[?(@.price > 10)].title", []interface{}{"B", "C"}},
+		{"$.store.book[?(@.title == 'B')].price", []interface{}{12.0}},
+		{`$.store.book[?(@.tags == ["x"])].title`, []interface{}{"A"}},
+		{`$.store.book[?(@.tags != ["x"])].title`, []interface{}{}},
+		{"$.store.book[?(@.note == 'a<b')].title", []interface{}{"B"}},
+		{"$.store.book[?(@.title > 'x==y')].title", []interface{}{}},
+		{`$.store.book[?(@["title"] != "a<b")].title`, []interface{}{"A", "B", "C"}},
+		{"$.store.book[-4]", []interface{}{}},
+		{"$.store.book[3]", []interface{}{}},
+		{"$.store.book[-5:1].title", []interface{}{"A"}},
+		{"$.store.*.title", []interface{}{"Bike"}},
+		{"$.store.missing", []interface{}{}},
+	}
+	for _, test := range tests {
+		values, err := QueryJSON(data, test.path)
+		if err != nil {
+			t.Errorf("QueryJSON(%s) error: %v", test.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(values, test.want) {
+			t.Errorf("QueryJSON(%s) = %v, want %v", test.path, values, test.want)
+		}
+	}
+
+	for _, path := range []string{"$.store[", "$.store..", "$.book[?(price)]", "$.book[x]"} {
+		if _, err := QueryJSON(data, path); err == nil {
+			t.Errorf("QueryJSON(%s) should fail", path)
+		}
+	}
+}
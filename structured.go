@@ -0,0 +1,702 @@
+package pagereader
+
+import (
+	"encoding/json"
+	"github.com/PuerkitoBio/goquery"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StructuredData Structured data published by the page
+type StructuredData struct {
+	JSONLD    []Entity    `json:"jsonld"`    // JSON-LD objects, @graph items are flattened
+	Microdata []Entity    `json:"microdata"` // Top level schema.org Microdata items
+	RDFa      []Entity    `json:"rdfa"`      // Top level RDFa Lite items
+	OpenGraph OpenGraph   `json:"openGraph"`
+	Twitter   TwitterCard `json:"twitter"`
+	Meta      Meta        `json:"meta"`
+	Errors    []string    `json:"errors,omitempty"` // Invalid JSON-LD blocks
+}
+
+// OpenGraph og:* meta properties
+type OpenGraph struct {
+	Title       string              `json:"title"`
+	Type        string              `json:"type"`
+	URL         string              `json:"url"`
+	Description string              `json:"description"`
+	SiteName    string              `json:"siteName"`
+	Locale      string              `json:"locale"`
+	Images      []string            `json:"images"`
+	Properties  map[string][]string `json:"properties"` // All og:* properties, e.g. og:image:width, og:price:amount
+}
+
+// TwitterCard twitter:* meta properties
+type TwitterCard struct {
+	Card        string            `json:"card"`
+	Site        string            `json:"site"`
+	Creator     string            `json:"creator"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	Properties  map[string]string `json:"properties"` // All twitter:* properties
+}
+
+// Meta Standard meta tags
+type Meta struct {
+	Title       string            `json:"title"` // <title>
+	Description string            `json:"description"`
+	Keywords    string            `json:"keywords"`
+	Robots      string            `json:"robots"`
+	Author      string            `json:"author"`
+	Canonical   string            `json:"canonical"` // <link rel="canonical">
+	Language    string            `json:"language"`  // <html lang>
+	Tags        map[string]string `json:"tags"`      // All meta tags with name or http-equiv, keys are lower case
+}
+
+// Product schema.org Product, values are kept as published, e.g. Price "99.5"
+type Product struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Brand       string   `json:"brand"`
+	SKU         string   `json:"sku"`
+	MPN         string   `json:"mpn"`
+	GTIN        string   `json:"gtin"` // gtin, gtin13, gtin12, gtin14 or gtin8
+	Category    string   `json:"category"`
+	URL         string   `json:"url"`
+	Images      []string `json:"images"`
+	RatingValue string   `json:"ratingValue"` // aggregateRating.ratingValue
+	ReviewCount string   `json:"reviewCount"` // aggregateRating.reviewCount, ratingCount if not found
+	Offers      []Offer  `json:"offers"`
+	Entity      Entity   `json:"-"` // Source entity for properties not listed above
+}
+
+// Offer schema.org Offer or AggregateOffer
+type Offer struct {
+	Price         string `json:"price"` // lowPrice of AggregateOffer if price is not found
+	LowPrice      string `json:"lowPrice"`
+	HighPrice     string `json:"highPrice"`
+	PriceCurrency string `json:"priceCurrency"`
+	Availability  string `json:"availability"`  // Short type, e.g. InStock
+	ItemCondition string `json:"itemCondition"` // Short type, e.g. NewCondition
+	Seller        string `json:"seller"`
+	URL           string `json:"url"`
+	Entity        Entity `json:"-"`
+}
+
+// Breadcrumb schema.org BreadcrumbList, items are sorted by position
+type Breadcrumb struct {
+	Items  []BreadcrumbItem `json:"items"`
+	Entity Entity           `json:"-"`
+}
+
+// BreadcrumbItem schema.org ListItem of BreadcrumbList
+type BreadcrumbItem struct {
+	Position int    `json:"position"` // Index + 1 if not published
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+}
+
+// Entity JSON-LD shaped object, Microdata and RDFa items are converted to the same shape, e.g.
+// {"@type": "Product", "name": "...", "offers": {"@type": "Offer", "price": "9.99"}}
+type Entity map[string]interface{}
+
+// Types Short types without vocabulary, e.g. https://schema.org/Product => Product
+func (e Entity) Types() []string {
+	types := make([]string, 0)
+	for _, v := range asSlice(e["@type"]) {
+		if s, ok := v.(string); ok {
+			types = append(types, shortType(s))
+		}
+	}
+	return types
+}
+
+// Is Whether entity has the schema type, vocabulary of t is ignored
+func (e Entity) Is(t string) bool {
+	t = shortType(t)
+	for _, typ := range e.Types() {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Text String value of key, the first value is used if it's a list, and name, @value, @id or url is used if it's an object
+func (e Entity) Text(key string) string {
+	values := e.Strings(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Strings String values of key, see Text
+func (e Entity) Strings(key string) []string {
+	values := make([]string, 0)
+	for _, v := range asSlice(e[key]) {
+		if s := entityText(v); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// Entity First object value of key, nil if not found
+func (e Entity) Entity(key string) Entity {
+	if entities := e.Entities(key); len(entities) > 0 {
+		return entities[0]
+	}
+	return nil
+}
+
+// Entities Object values of key
+func (e Entity) Entities(key string) []Entity {
+	entities := make([]Entity, 0)
+	for _, v := range asSlice(e[key]) {
+		if entity := asEntity(v); entity != nil {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// Decode Decode entity into v by JSON
+func (e Entity) Decode(v interface{}) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Entities All top level JSON-LD, Microdata and RDFa entities
+func (d StructuredData) Entities() []Entity {
+	entities := make([]Entity, 0, len(d.JSONLD)+len(d.Microdata)+len(d.RDFa))
+	entities = append(entities, d.JSONLD...)
+	entities = append(entities, d.Microdata...)
+	return append(entities, d.RDFa...)
+}
+
+// FindType Entities of schema type in any depth, e.g. Product, Offer, BreadcrumbList. Entities are in document order,
+// nested entities follow their parent in the order of sorted property names.
+func (d StructuredData) FindType(t string) []Entity {
+	entities := make([]Entity, 0)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch value := v.(type) {
+		case []interface{}:
+			for _, item := range value {
+				walk(item)
+			}
+		case []Entity:
+			for _, item := range value {
+				walk(item)
+			}
+		default:
+			entity := asEntity(v)
+			if entity == nil {
+				return
+			}
+			if entity.Is(t) {
+				entities = append(entities, entity)
+			}
+			// Keys are sorted so nested entities are found in the same order every time
+			for _, k := range sortedKeys(entity) {
+				if k != "@type" && k != "@context" {
+					walk(entity[k])
+				}
+			}
+		}
+	}
+	walk(d.Entities())
+	return entities
+}
+
+// First The first entity of schema type, nil if not found
+func (d StructuredData) First(t string) Entity {
+	if entities := d.FindType(t); len(entities) > 0 {
+		return entities[0]
+	}
+	return nil
+}
+
+// Products All Product entities of JSON-LD, Microdata and RDFa
+func (d StructuredData) Products() []Product {
+	entities := d.FindType("Product")
+	products := make([]Product, len(entities))
+	for i, e := range entities {
+		products[i] = newProduct(e)
+	}
+	return products
+}
+
+// Offers All Offer and AggregateOffer entities in any depth, including offers of products
+func (d StructuredData) Offers() []Offer {
+	offers := make([]Offer, 0)
+	for _, e := range d.FindType("Offer") {
+		offers = append(offers, newOffer(e))
+	}
+	for _, e := range d.FindType("AggregateOffer") {
+		offers = append(offers, newOffer(e))
+	}
+	return offers
+}
+
+// Breadcrumbs All BreadcrumbList entities
+func (d StructuredData) Breadcrumbs() []Breadcrumb {
+	entities := d.FindType("BreadcrumbList")
+	breadcrumbs := make([]Breadcrumb, len(entities))
+	for i, e := range entities {
+		breadcrumbs[i] = newBreadcrumb(e)
+	}
+	return breadcrumbs
+}
+
+// StructuredData Parse JSON-LD, Microdata, RDFa Lite, OpenGraph, Twitter Card and meta tags from Doc
+func (pr PageReader) StructuredData() *StructuredData {
+	d := &StructuredData{
+		JSONLD:    make([]Entity, 0),
+		Microdata: make([]Entity, 0),
+		RDFa:      make([]Entity, 0),
+		OpenGraph: OpenGraph{Images: make([]string, 0), Properties: make(map[string][]string)},
+		Twitter:   TwitterCard{Properties: make(map[string]string)},
+		Meta:      Meta{Tags: make(map[string]string)},
+	}
+	if pr.Doc == nil {
+		return d
+	}
+	doc := pr.Doc.Selection
+	finish := pr.startExtraction(pr.extractionContext(), "StructuredData")
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var v interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &v); err != nil {
+			d.Errors = append(d.Errors, err.Error())
+			pr.debug("Parse JSON-LD failed", F("index", i), F("error", err))
+			return
+		}
+		d.JSONLD = append(d.JSONLD, flattenJSONLD(v)...)
+	})
+	doc.Find("[itemscope]").Not("[itemprop]").Each(func(i int, s *goquery.Selection) {
+		d.Microdata = append(d.Microdata, microdataItem(s))
+	})
+	doc.Find("[typeof]").Each(func(i int, s *goquery.Selection) {
+		if _, ok := s.Attr("property"); !ok || s.ParentsFiltered("[typeof]").Length() == 0 {
+			d.RDFa = append(d.RDFa, rdfaItem(s))
+		}
+	})
+	pr.parseMeta(d)
+
+	found := len(d.Entities()) > 0 || len(d.OpenGraph.Properties) > 0 || len(d.Twitter.Properties) > 0 || len(d.Meta.Tags) > 0
+	finish(found, 0, nil)
+	pr.debug("Structured data", F("jsonld", len(d.JSONLD)), F("microdata", len(d.Microdata)), F("rdfa", len(d.RDFa)), F("og", len(d.OpenGraph.Properties)))
+	return d
+}
+
+func (pr PageReader) parseMeta(d *StructuredData) {
+	doc := pr.Doc.Selection
+	d.Meta.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	d.Meta.Language, _ = doc.Find("html").Attr("lang")
+	if href, ok := doc.Find(`link[rel="canonical"]`).Attr("href"); ok {
+		d.Meta.Canonical = strings.TrimSpace(href)
+	}
+	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
+		content, ok := s.Attr("content")
+		if !ok {
+			return
+		}
+		content = strings.TrimSpace(content)
+		// OpenGraph uses property, but name is common too
+		key := s.AttrOr("property", "")
+		if key == "" {
+			key = s.AttrOr("name", s.AttrOr("http-equiv", ""))
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		switch {
+		case key == "":
+		case strings.HasPrefix(key, "og:"):
+			d.OpenGraph.Properties[key] = append(d.OpenGraph.Properties[key], content)
+		case strings.HasPrefix(key, "twitter:"):
+			if _, exists := d.Twitter.Properties[key]; !exists {
+				d.Twitter.Properties[key] = content
+			}
+		default:
+			if _, exists := d.Meta.Tags[key]; !exists {
+				d.Meta.Tags[key] = content
+			}
+		}
+	})
+
+	og := d.OpenGraph.Properties
+	first := func(key string) string {
+		if values := og[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	d.OpenGraph.Title = first("og:title")
+	d.OpenGraph.Type = first("og:type")
+	d.OpenGraph.URL = first("og:url")
+	d.OpenGraph.Description = first("og:description")
+	d.OpenGraph.SiteName = first("og:site_name")
+	d.OpenGraph.Locale = first("og:locale")
+	// og:image:url is an alias of og:image, and pages often set both to the same URL
+	seen := make(map[string]bool)
+	for _, image := range append(append([]string{}, og["og:image"]...), og["og:image:url"]...) {
+		if image != "" && !seen[image] {
+			seen[image] = true
+			d.OpenGraph.Images = append(d.OpenGraph.Images, image)
+		}
+	}
+
+	twitter := d.Twitter.Properties
+	d.Twitter.Card = twitter["twitter:card"]
+	d.Twitter.Site = twitter["twitter:site"]
+	d.Twitter.Creator = twitter["twitter:creator"]
+	d.Twitter.Title = twitter["twitter:title"]
+	d.Twitter.Description = twitter["twitter:description"]
+	d.Twitter.Image = twitter["twitter:image"]
+	if d.Twitter.Image == "" {
+		d.Twitter.Image = twitter["twitter:image:src"]
+	}
+
+	tags := d.Meta.Tags
+	d.Meta.Description = tags["description"]
+	d.Meta.Keywords = tags["keywords"]
+	d.Meta.Robots = tags["robots"]
+	d.Meta.Author = tags["author"]
+}
+
+// flattenJSONLD Split arrays and @graph into entities, @context of container is kept in every graph item
+func flattenJSONLD(v interface{}) []Entity {
+	entities := make([]Entity, 0)
+	switch value := v.(type) {
+	case []interface{}:
+		for _, item := range value {
+			entities = append(entities, flattenJSONLD(item)...)
+		}
+	case map[string]interface{}:
+		graph, ok := value["@graph"].([]interface{})
+		if !ok {
+			return append(entities, value)
+		}
+		for _, item := range graph {
+			if m, ok := item.(map[string]interface{}); ok {
+				if _, exists := m["@context"]; !exists && value["@context"] != nil {
+					m["@context"] = value["@context"]
+				}
+				entities = append(entities, m)
+			}
+		}
+	}
+	return entities
+}
+
+// microdataItem Convert itemscope element to entity, properties of nested items are not included
+func microdataItem(s *goquery.Selection) Entity {
+	item := Entity{}
+	if types := strings.Fields(s.AttrOr("itemtype", "")); len(types) > 0 {
+		item["@type"] = singleOrList(types)
+	}
+	if id, ok := s.Attr("itemid"); ok {
+		item["@id"] = strings.TrimSpace(id)
+	}
+	properties := make(map[string][]interface{})
+	names := make([]string, 0)
+	var walk func(s *goquery.Selection)
+	walk = func(s *goquery.Selection) {
+		s.Children().Each(func(i int, child *goquery.Selection) {
+			_, scope := child.Attr("itemscope")
+			if prop, ok := child.Attr("itemprop"); ok {
+				var value interface{}
+				if scope {
+					value = microdataItem(child)
+				} else {
+					value = elementValue(child)
+				}
+				for _, name := range strings.Fields(prop) {
+					if _, exists := properties[name]; !exists {
+						names = append(names, name)
+					}
+					properties[name] = append(properties[name], value)
+				}
+			}
+			if !scope {
+				walk(child)
+			}
+		})
+	}
+	walk(s)
+	for _, name := range names {
+		item[name] = singleOrList(properties[name])
+	}
+	return item
+}
+
+// rdfaItem Convert typeof element to entity, properties of nested items are not included
+func rdfaItem(s *goquery.Selection) Entity {
+	item := Entity{}
+	vocab := ""
+	if v := s.Closest("[vocab]"); v.Length() > 0 {
+		vocab = v.AttrOr("vocab", "")
+	}
+	prefixes := rdfaPrefixes(s)
+	expand := func(name string) string {
+		if i := strings.Index(name, ":"); i > 0 && !strings.Contains(name, "://") {
+			if iri, ok := prefixes[name[:i]]; ok {
+				return iri + name[i+1:]
+			}
+			return name
+		}
+		if strings.Contains(name, "://") {
+			return name
+		}
+		return vocab + name
+	}
+	if types := strings.Fields(s.AttrOr("typeof", "")); len(types) > 0 {
+		for i, t := range types {
+			types[i] = expand(t)
+		}
+		item["@type"] = singleOrList(types)
+	}
+	// href and src of typed element are the item itself, e.g. <a property="item" typeof="WebPage" href="/">
+	for _, attr := range []string{"resource", "href", "src"} {
+		if id, ok := s.Attr(attr); ok {
+			item["@id"] = strings.TrimSpace(id)
+			break
+		}
+	}
+	properties := make(map[string][]interface{})
+	names := make([]string, 0)
+	var walk func(s *goquery.Selection)
+	walk = func(s *goquery.Selection) {
+		s.Children().Each(func(i int, child *goquery.Selection) {
+			_, scope := child.Attr("typeof")
+			if prop, ok := child.Attr("property"); ok {
+				var value interface{}
+				switch {
+				case scope:
+					value = rdfaItem(child)
+				case child.AttrOr("content", "") != "":
+					value = strings.TrimSpace(child.AttrOr("content", ""))
+				case child.AttrOr("resource", "") != "":
+					value = strings.TrimSpace(child.AttrOr("resource", ""))
+				default:
+					value = elementValue(child)
+				}
+				for _, name := range strings.Fields(prop) {
+					// Short names are kept for the default vocabulary, e.g. name instead of https://schema.org/name
+					if strings.Contains(name, ":") {
+						name = shortType(expand(name))
+					}
+					if _, exists := properties[name]; !exists {
+						names = append(names, name)
+					}
+					properties[name] = append(properties[name], value)
+				}
+			}
+			if !scope {
+				walk(child)
+			}
+		})
+	}
+	walk(s)
+	for _, name := range names {
+		item[name] = singleOrList(properties[name])
+	}
+	return item
+}
+
+// rdfaPrefixes Prefix mappings of element and it's ancestors, nearer mappings win, "schema" is predefined
+func rdfaPrefixes(s *goquery.Selection) map[string]string {
+	prefixes := map[string]string{"schema": "https://schema.org/"}
+	elements := s.ParentsFiltered("[prefix]")
+	for i := elements.Length() - 1; i >= -1; i-- {
+		e := s
+		if i >= 0 {
+			e = elements.Eq(i)
+		}
+		fields := strings.Fields(e.AttrOr("prefix", ""))
+		for j := 0; j+1 < len(fields); j += 2 {
+			prefixes[strings.TrimSuffix(fields[j], ":")] = fields[j+1]
+		}
+	}
+	return prefixes
+}
+
+// elementValue Property value of element by Microdata rules
+func elementValue(s *goquery.Selection) string {
+	attr := ""
+	switch goquery.NodeName(s) {
+	case "meta":
+		attr = "content"
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		attr = "src"
+	case "a", "area", "link":
+		attr = "href"
+	case "object":
+		attr = "data"
+	case "data", "meter":
+		attr = "value"
+	case "time":
+		attr = "datetime"
+	}
+	if attr != "" {
+		if value, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	if content, ok := s.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+	return strings.TrimSpace(s.Text())
+}
+
+func newProduct(e Entity) Product {
+	p := Product{
+		Name:        e.Text("name"),
+		Description: e.Text("description"),
+		Brand:       e.Text("brand"),
+		SKU:         e.Text("sku"),
+		MPN:         e.Text("mpn"),
+		GTIN:        firstText(e, "gtin", "gtin13", "gtin12", "gtin14", "gtin8"),
+		Category:    e.Text("category"),
+		URL:         e.Text("url"),
+		Images:      e.Strings("image"),
+		Offers:      make([]Offer, 0),
+		Entity:      e,
+	}
+	if rating := e.Entity("aggregateRating"); rating != nil {
+		p.RatingValue = rating.Text("ratingValue")
+		p.ReviewCount = firstText(rating, "reviewCount", "ratingCount")
+	}
+	for _, offer := range e.Entities("offers") {
+		p.Offers = append(p.Offers, newOffer(offer))
+	}
+	return p
+}
+
+func newOffer(e Entity) Offer {
+	return Offer{
+		Price:         firstText(e, "price", "lowPrice"),
+		LowPrice:      e.Text("lowPrice"),
+		HighPrice:     e.Text("highPrice"),
+		PriceCurrency: e.Text("priceCurrency"),
+		Availability:  shortType(e.Text("availability")),
+		ItemCondition: shortType(e.Text("itemCondition")),
+		Seller:        e.Text("seller"),
+		URL:           e.Text("url"),
+		Entity:        e,
+	}
+}
+
+func newBreadcrumb(e Entity) Breadcrumb {
+	b := Breadcrumb{Items: make([]BreadcrumbItem, 0), Entity: e}
+	for i, item := range e.Entities("itemListElement") {
+		bi := BreadcrumbItem{Position: i + 1, Name: item.Text("name")}
+		if position, err := strconv.Atoi(item.Text("position")); err == nil {
+			bi.Position = position
+		}
+		// item is the URL, or a Thing with @id and name
+		if thing := item.Entity("item"); thing != nil {
+			bi.URL = firstText(thing, "@id", "url")
+			if bi.Name == "" {
+				bi.Name = thing.Text("name")
+			}
+		} else {
+			bi.URL = item.Text("item")
+		}
+		if bi.URL == "" {
+			bi.URL = item.Text("url")
+		}
+		b.Items = append(b.Items, bi)
+	}
+	sort.SliceStable(b.Items, func(i, j int) bool {
+		return b.Items[i].Position < b.Items[j].Position
+	})
+	return b
+}
+
+// firstText Text of the first key which has value
+func firstText(e Entity, keys ...string) string {
+	for _, key := range keys {
+		if s := e.Text(key); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func shortType(t string) string {
+	for _, prefix := range []string{"http://schema.org/", "https://schema.org/", "schema:"} {
+		if strings.HasPrefix(t, prefix) {
+			return strings.TrimPrefix(t, prefix)
+		}
+	}
+	return t
+}
+
+func singleOrList(values interface{}) interface{} {
+	switch v := values.(type) {
+	case []string:
+		if len(v) == 1 {
+			return v[0]
+		}
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	case []interface{}:
+		if len(v) == 1 {
+			return v[0]
+		}
+	}
+	return values
+}
+
+func asSlice(v interface{}) []interface{} {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return value
+	case []Entity:
+		list := make([]interface{}, len(value))
+		for i, e := range value {
+			list[i] = e
+		}
+		return list
+	}
+	return []interface{}{v}
+}
+
+func asEntity(v interface{}) Entity {
+	switch value := v.(type) {
+	case Entity:
+		return value
+	case map[string]interface{}:
+		return value
+	}
+	return nil
+}
+
+func entityText(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64, bool:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+	if entity := asEntity(v); entity != nil {
+		for _, key := range []string{"name", "@value", "@id", "url"} {
+			if s := entity.Text(key); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
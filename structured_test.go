@@ -0,0 +1,193 @@
+package pagereader
+
+import (
+	"reflect"
+	"testing"
+)
+
+const structuredHtml = `<html lang="en"><head>
+<title> Coffee Maker </title>
+<meta name="description" content="Best coffee maker">
+<meta name="Robots" content="index, follow">
+<meta property="og:title" content="Coffee Maker">
+<meta property="og:type" content="product">
+<meta property="og:image" content="https://example.com/1.jpg">
+<meta property="og:image" content="https://example.com/2.jpg">
+<meta property="og:image:url" content="https://example.com/1.jpg">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image:src" content="https://example.com/t.jpg">
+<link rel="canonical" href="https://example.com/p/1">
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "Product", "name": "Coffee Maker", "brand": {"@type": "Brand", "name": "Acme"},
+   "offers": [{"@type": "Offer", "price": 99.5, "priceCurrency": "USD"}]},
+  {"@type": "BreadcrumbList", "itemListElement": [
+    {"@type": "ListItem", "position": 1, "name": "Home"},
+    {"@type": "ListItem", "position": 2, "name": "Kitchen"}]}
+]}
+</script>
+<script type="application/ld+json">{invalid</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/Product" itemid="urn:p:2">
+  <h1 itemprop="name">Tea Kettle</h1>
+  <img itemprop="image" src="/k1.jpg"><img itemprop="image" src="/k2.jpg">
+  <div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+    <span itemprop="price" content="19.99">$19.99</span>
+    <link itemprop="availability" href="https://schema.org/InStock">
+  </div>
+</div>
+<div vocab="https://schema.org/" typeof="Person">
+  <span property="name">Jane</span>
+  <div property="address" typeof="PostalAddress"><span property="addressLocality">Paris</span></div>
+  <a property="schema:url" href="https://jane.example.com">home</a>
+</div>
+</body></html>`
+
+func TestPageReader_StructuredData(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(structuredHtml)
+	d := pr.StructuredData()
+
+	if len(d.JSONLD) != 2 || len(d.Microdata) != 1 || len(d.RDFa) != 1 || len(d.Errors) != 1 {
+		t.Fatalf("unexpected entities: %d, %d, %d, %v", len(d.JSONLD), len(d.Microdata), len(d.RDFa), d.Errors)
+	}
+	if d.JSONLD[0]["@context"] != "https://schema.org" {
+		t.Errorf("@context should be kept in graph items: %v", d.JSONLD[0])
+	}
+
+	products := d.FindType("Product")
+	if len(products) != 2 || products[0].Text("name") != "Coffee Maker" || products[1].Text("name") != "Tea Kettle" {
+		t.Fatalf("unexpected products: %v", products)
+	}
+	if products[0].Text("brand") != "Acme" || products[0].Entity("offers").Text("price") != "99.5" {
+		t.Errorf("unexpected product: %v", products[0])
+	}
+	if !reflect.DeepEqual(products[1].Strings("image"), []string{"/k1.jpg", "/k2.jpg"}) || products[1].Text("@id") != "urn:p:2" {
+		t.Errorf("unexpected microdata product: %v", products[1])
+	}
+	offers := d.FindType("https://schema.org/Offer")
+	if len(offers) != 2 || offers[1].Text("price") != "19.99" || offers[1].Text("availability") != "https://schema.org/InStock" {
+		t.Errorf("unexpected offers: %v", offers)
+	}
+	var breadcrumb struct {
+		Items []struct {
+			Position int    `json:"position"`
+			Name     string `json:"name"`
+		} `json:"itemListElement"`
+	}
+	if err := d.First("BreadcrumbList").Decode(&breadcrumb); err != nil || len(breadcrumb.Items) != 2 || breadcrumb.Items[1].Name != "Kitchen" {
+		t.Errorf("unexpected breadcrumb: %+v, %v", breadcrumb, err)
+	}
+
+	person := d.First("Person")
+	if person == nil || person.Text("name") != "Jane" || person.Entity("address").Text("addressLocality") != "Paris" || person.Text("url") != "https://jane.example.com" {
+		t.Errorf("unexpected person: %v", person)
+	}
+	if !person.Entity("address").Is("PostalAddress") || d.First("Organization") != nil {
+		t.Errorf("unexpected type lookup")
+	}
+
+	if d.OpenGraph.Title != "Coffee Maker" || d.OpenGraph.Type != "product" || len(d.OpenGraph.Images) != 2 {
+		t.Errorf("unexpected OpenGraph: %+v", d.OpenGraph)
+	}
+	if d.Twitter.Card != "summary_large_image" || d.Twitter.Image != "https://example.com/t.jpg" {
+		t.Errorf("unexpected Twitter Card: %+v", d.Twitter)
+	}
+	if d.Meta.Title != "Coffee Maker" || d.Meta.Description != "Best coffee maker" || d.Meta.Robots != "index, follow" ||
+		d.Meta.Canonical != "https://example.com/p/1" || d.Meta.Language != "en" {
+		t.Errorf("unexpected meta: %+v", d.Meta)
+	}
+}
+
+func TestStructuredData_Typed(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(structuredHtml)
+	d := pr.StructuredData()
+
+	products := d.Products()
+	if len(products) != 2 || products[0].Name != "Coffee Maker" || products[0].Brand != "Acme" || products[1].Name != "Tea Kettle" {
+		t.Fatalf("unexpected products: %+v", products)
+	}
+	if offers := products[0].Offers; len(offers) != 1 || offers[0].Price != "99.5" || offers[0].PriceCurrency != "USD" {
+		t.Errorf("unexpected JSON-LD offers: %+v", offers)
+	}
+	if offers := products[1].Offers; len(offers) != 1 || offers[0].Price != "19.99" || offers[0].Availability != "InStock" {
+		t.Errorf("unexpected Microdata offers: %+v", offers)
+	}
+	if !reflect.DeepEqual(products[1].Images, []string{"/k1.jpg", "/k2.jpg"}) || products[1].Entity.Text("@id") != "urn:p:2" {
+		t.Errorf("unexpected Microdata product: %+v", products[1])
+	}
+	if offers := d.Offers(); len(offers) != 2 {
+		t.Errorf("unexpected offers: %+v", offers)
+	}
+	breadcrumbs := d.Breadcrumbs()
+	if len(breadcrumbs) != 1 || !reflect.DeepEqual(breadcrumbs[0].Items, []BreadcrumbItem{{Position: 1, Name: "Home"}, {Position: 2, Name: "Kitchen"}}) {
+		t.Errorf("unexpected breadcrumbs: %+v", breadcrumbs)
+	}
+
+	pr.SetHtml(`<html><body>
+<div itemscope itemtype="https://schema.org/Product">
+  <span itemprop="name">Mug</span><meta itemprop="gtin13" content="0012345678905">
+  <div itemprop="aggregateRating" itemscope itemtype="https://schema.org/AggregateRating">
+    <span itemprop="ratingValue">4.5</span><span itemprop="ratingCount">12</span>
+  </div>
+  <div itemprop="offers" itemscope itemtype="https://schema.org/AggregateOffer">
+    <meta itemprop="lowPrice" content="5"><meta itemprop="highPrice" content="9"><meta itemprop="priceCurrency" content="EUR">
+  </div>
+</div>
+<ol vocab="https://schema.org/" typeof="BreadcrumbList">
+  <li property="itemListElement" typeof="ListItem">
+    <a property="item" typeof="WebPage" href="/kitchen"><span property="name">Kitchen</span></a><meta property="position" content="2">
+  </li>
+  <li property="itemListElement" typeof="ListItem">
+    <a property="item" href="/"><span property="name">Home</span></a><meta property="position" content="1">
+  </li>
+</ol>
+</body></html>`)
+	d = pr.StructuredData()
+	products = d.Products()
+	if len(products) != 1 || products[0].GTIN != "0012345678905" || products[0].RatingValue != "4.5" || products[0].ReviewCount != "12" {
+		t.Fatalf("unexpected products: %+v", products)
+	}
+	if offers := products[0].Offers; len(offers) != 1 || offers[0].Price != "5" || offers[0].HighPrice != "9" || offers[0].PriceCurrency != "EUR" {
+		t.Errorf("unexpected aggregate offers: %+v", offers)
+	}
+	breadcrumbs = d.Breadcrumbs()
+	if len(breadcrumbs) != 1 || !reflect.DeepEqual(breadcrumbs[0].Items, []BreadcrumbItem{{Position: 1, Name: "Home", URL: "/"}, {Position: 2, Name: "Kitchen", URL: "/kitchen"}}) {
+		t.Errorf("unexpected RDFa breadcrumbs: %+v", breadcrumbs)
+	}
+}
+
+func TestStructuredData_FindTypeOrder(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(`<html><head><script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Product", "name": "Kettle",
+ "offers": {"@type": "Offer", "price": "1"},
+ "isRelatedTo": {"@type": "Product", "name": "Mug", "offers": {"@type": "Offer", "price": "4"}},
+ "hasVariant": [
+   {"@type": "Product", "name": "Red Kettle", "offers": {"@type": "Offer", "price": "2"}},
+   {"@type": "Product", "name": "Blue Kettle", "offers": {"@type": "Offer", "price": "3"}}
+ ],
+ "subjectOf": {"@type": "Review", "offers": {"@type": "Offer", "price": "5"}}}
+</script></head></html>`)
+	for i := 0; i < 20; i++ {
+		d := pr.StructuredData()
+		prices := make([]string, 0)
+		for _, offer := range d.Offers() {
+			prices = append(prices, offer.Price)
+		}
+		if !reflect.DeepEqual(prices, []string{"2", "3", "4", "1", "5"}) {
+			t.Fatalf("unexpected offer order: %v", prices)
+		}
+		names := make([]string, 0)
+		for _, product := range d.Products() {
+			names = append(names, product.Name)
+		}
+		if !reflect.DeepEqual(names, []string{"Kettle", "Red Kettle", "Blue Kettle", "Mug"}) {
+			t.Fatalf("unexpected product order: %v", names)
+		}
+		if offer := d.First("Offer"); offer.Text("price") != "2" {
+			t.Fatalf("unexpected first offer: %v", offer)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package pagereader
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"strconv"
+	"strings"
+)
+
+// Upper limits of colspan and rowspan, same as browsers
+const (
+	maxColspan = 1000
+	maxRowspan = 65534
+)
+
+// tableRow Row of table with raw th/td cells
+type tableRow struct {
+	cells  *goquery.Selection
+	header bool // In thead or all cells are th
+	group  int  // Row group, rows in the same thead, tbody or tfoot, or consecutive rows directly in table
+}
+
+// Table Cell text of the first table matched by the first selector which has any rows, rowspan and colspan are
+// expanded so every row has the same number of columns. Selector can match the table or an element containing it.
+// Tables are often optional, so artifacts are not dumped in Debug mode if nothing matched.
+func (pr PageReader) Table(selector string, selectors ...string) [][]string {
+	var grid [][]string
+	pr.lookupIn(pr.docRoot(), "Table", append([]string{selector}, selectors...), func(s *goquery.Selection) ([]string, bool) {
+		rows := tableRows(firstTable(s))
+		grid = expandTable(rows)
+		return flatten(grid), len(grid) > 0
+	})
+	if grid == nil {
+		return make([][]string, 0)
+	}
+	return grid
+}
+
+// TableRecords Rows of table keyed by header cells. Header is rows in thead, or leading rows of th cells, or the first
+// row; texts of multi-row headers are joined with space, e.g. "Price USD". Empty header is keyed by column number
+// from 1, duplicated header by "<header> <n>". Rows without any text are skipped.
+func (pr PageReader) TableRecords(selector string, selectors ...string) []map[string]string {
+	records := make([]map[string]string, 0)
+	pr.lookupIn(pr.docRoot(), "TableRecords", append([]string{selector}, selectors...), func(s *goquery.Selection) ([]string, bool) {
+		rows := tableRows(firstTable(s))
+		grid := expandTable(rows)
+		n := 0
+		for n < len(rows) && rows[n].header {
+			n++
+		}
+		if n == 0 && len(rows) > 1 {
+			n = 1
+		}
+		if n == 0 || n == len(grid) {
+			return nil, false
+		}
+		keys := tableKeys(grid[:n])
+		for _, row := range grid[n:] {
+			if strings.Join(row, "") == "" {
+				continue
+			}
+			record := make(map[string]string, len(keys))
+			for i, key := range keys {
+				record[key] = row[i]
+			}
+			records = append(records, record)
+		}
+		return flatten(grid[n:]), len(records) > 0
+	})
+	return records
+}
+
+// KeyValues Key/value pairs of two-column tables and dl lists matched by the first selector which has any pairs,
+// selector can match the tables and lists or elements containing them. Rows with an even number of cells are read as
+// pairs, e.g. <tr><th>Brand</th><td>Acme</td></tr>, other rows such as section titles are skipped. Multiple dd of
+// the same dt are joined with ", ". Trailing colon of key is removed, and the first value wins for duplicated keys.
+func (pr PageReader) KeyValues(selector string, selectors ...string) map[string]string {
+	pairs := make(map[string]string)
+	pr.lookupIn(pr.docRoot(), "KeyValues", append([]string{selector}, selectors...), func(s *goquery.Selection) ([]string, bool) {
+		values := make([]string, 0)
+		add := func(key, value string) {
+			key = strings.TrimSpace(strings.TrimSuffix(key, ":"))
+			if _, exists := pairs[key]; key == "" || exists {
+				return
+			}
+			pairs[key] = value
+			values = append(values, key, value)
+		}
+		s.Find("table, dl").AddSelection(s.Filter("table, dl")).Each(func(i int, list *goquery.Selection) {
+			if goquery.NodeName(list) == "dl" {
+				dlPairs(list, add)
+				return
+			}
+			for _, row := range tableRows(list) {
+				cells := row.cells
+				if cells.Length()%2 != 0 {
+					continue
+				}
+				for j := 0; j < cells.Length(); j += 2 {
+					add(cellText(cells.Eq(j)), cellText(cells.Eq(j+1)))
+				}
+			}
+		})
+		return values, len(values) > 0
+	})
+	return pairs
+}
+
+// firstTable The first element if it's a table, else the first table in it
+func firstTable(s *goquery.Selection) *goquery.Selection {
+	s = s.First()
+	if goquery.NodeName(s) == "table" {
+		return s
+	}
+	return s.Find("table").First()
+}
+
+// tableRows Rows of table in document order, rows of nested tables are excluded
+func tableRows(table *goquery.Selection) []tableRow {
+	rows := make([]tableRow, 0)
+	group, inGroup := 0, false
+	add := func(tr *goquery.Selection, inHead bool) {
+		cells := tr.ChildrenFiltered("th, td")
+		if cells.Length() == 0 {
+			return
+		}
+		rows = append(rows, tableRow{cells: cells, header: inHead || cells.Length() == cells.Filter("th").Length(), group: group})
+	}
+	table.Children().Each(func(i int, child *goquery.Selection) {
+		switch goquery.NodeName(child) {
+		case "tr":
+			if !inGroup {
+				group++
+				inGroup = true
+			}
+			add(child, false)
+		case "thead", "tbody", "tfoot":
+			group++
+			inGroup = false
+			inHead := goquery.NodeName(child) == "thead"
+			child.ChildrenFiltered("tr").Each(func(j int, tr *goquery.Selection) {
+				add(tr, inHead)
+			})
+		}
+	})
+	// Only leading rows can be header, a row with td ends the header
+	for i := range rows {
+		if !rows[i].header {
+			for j := i; j < len(rows); j++ {
+				rows[j].header = false
+			}
+			break
+		}
+	}
+	return rows
+}
+
+// pending Cell spanned to rows below
+type pending struct {
+	text string
+	rows int // Remaining rows
+}
+
+// expandTable Expand rowspan and colspan, a spanned cell's text is copied to every slot it covers. Rowspan doesn't cross
+// row groups, and rowspan="0" spans to the end of the row group, same as browsers.
+func expandTable(rows []tableRow) [][]string {
+	grid := make([][]string, 0, len(rows))
+	spans := make(map[int]*pending)
+	width := 0
+	for r, row := range rows {
+		if r > 0 && row.group != rows[r-1].group {
+			spans = make(map[int]*pending)
+		}
+		// Rows left in the row group, including this one
+		left := 1
+		for left < len(rows)-r && rows[r+left].group == row.group {
+			left++
+		}
+		line := make([]string, 0)
+		fill := func() {
+			for p, ok := spans[len(line)]; ok; p, ok = spans[len(line)] {
+				line = append(line, p.text)
+				if p.rows--; p.rows == 0 {
+					delete(spans, len(line)-1)
+				}
+			}
+		}
+		row.cells.Each(func(i int, cell *goquery.Selection) {
+			fill()
+			text := cellText(cell)
+			colspan := span(cell, "colspan", 1, maxColspan)
+			rowspan := span(cell, "rowspan", 0, maxRowspan)
+			if rowspan == 0 || rowspan > left {
+				rowspan = left
+			}
+			for j := 0; j < colspan; j++ {
+				if rowspan > 1 {
+					spans[len(line)] = &pending{text: text, rows: rowspan - 1}
+				}
+				line = append(line, text)
+			}
+		})
+		// Cells spanned from rows above on the right side
+		for spannedFrom(spans, len(line)) {
+			if _, ok := spans[len(line)]; ok {
+				fill()
+			} else {
+				line = append(line, "")
+			}
+		}
+		if len(line) > width {
+			width = len(line)
+		}
+		grid = append(grid, line)
+	}
+	for i := range grid {
+		for len(grid[i]) < width {
+			grid[i] = append(grid[i], "")
+		}
+	}
+	return grid
+}
+
+// spannedFrom Whether any column from col is spanned from rows above
+func spannedFrom(spans map[int]*pending, col int) bool {
+	for c := range spans {
+		if c >= col {
+			return true
+		}
+	}
+	return false
+}
+
+// tableKeys Record keys of header rows
+func tableKeys(header [][]string) []string {
+	keys := make([]string, len(header[0]))
+	seen := make(map[string]int)
+	for col := range keys {
+		parts := make([]string, 0, len(header))
+		for _, row := range header {
+			if text := row[col]; text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+				parts = append(parts, text)
+			}
+		}
+		key := strings.Join(parts, " ")
+		if key == "" {
+			key = strconv.Itoa(col + 1)
+		}
+		if seen[key]++; seen[key] > 1 {
+			key += " " + strconv.Itoa(seen[key])
+		}
+		keys[col] = key
+	}
+	return keys
+}
+
+// dlPairs Call add for every dt/dd group of dl, dt and dd can be wrapped in div
+func dlPairs(dl *goquery.Selection, add func(key, value string)) {
+	keys := make([]string, 0)
+	values := make([]string, 0)
+	flush := func() {
+		for _, key := range keys {
+			add(key, strings.Join(values, ", "))
+		}
+		keys, values = keys[:0], values[:0]
+	}
+	dl.Find("dt, dd").Each(func(i int, s *goquery.Selection) {
+		if s.Closest("dl").Get(0) != dl.Get(0) {
+			return
+		}
+		text := cellText(s)
+		if goquery.NodeName(s) == "dt" {
+			if len(values) > 0 {
+				flush()
+			}
+			keys = append(keys, text)
+		} else if text != "" {
+			values = append(values, text)
+		}
+	})
+	flush()
+}
+
+// cellText Text of cell with whitespace collapsed, script and style are ignored, and so are invisible direction marks.
+// Cells of nested tables are separated by space instead of glued to the text around them.
+func cellText(cell *goquery.Selection) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style"):
+			return
+		}
+		separated := n.Type == html.ElementNode && (n.Data == "table" || n.Data == "tr" || n.Data == "th" || n.Data == "td")
+		if separated {
+			b.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if separated {
+			b.WriteByte(' ')
+		}
+	}
+	for _, n := range cell.Nodes {
+		walk(n)
+	}
+	text := strings.NewReplacer("\u200e", "", "\u200f", "", "\u200b", "").Replace(b.String())
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// span Value of rowspan or colspan clamped to max, 1 if invalid or less than min
+func span(cell *goquery.Selection, name string, min, max int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(cell.AttrOr(name, "1")))
+	switch {
+	case err != nil || n < min:
+		return 1
+	case n > max:
+		return max
+	}
+	return n
+}
+
+func flatten(grid [][]string) []string {
+	values := make([]string, 0)
+	for _, row := range grid {
+		values = append(values, row...)
+	}
+	return values
+}
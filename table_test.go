@@ -0,0 +1,116 @@
+package pagereader
+
+import (
+	"reflect"
+	"testing"
+)
+
+const tableHtml = `<html><body>
+<table id="prices">
+  <caption>Prices</caption>
+  <thead>
+    <tr><th rowspan="2">Model</th><th colspan="2">Price</th><th rowspan="2"></th></tr>
+    <tr><th>USD</th><th>EUR</th></tr>
+  </thead>
+  <tbody>
+    <tr><td rowspan="2">K1</td><td>10</td><td>9</td><td>new</td></tr>
+    <tr><td colspan="2">n/a <script>track()</script></td><td>old</td></tr>
+    <tr><td></td><td></td><td></td><td></td></tr>
+    <tr><td>K2</td><td>20<table><tr><td>nested</td></tr></table></td></tr>
+  </tbody>
+</table>
+<div id="productDetails">
+  <table>
+    <tr><th colspan="2">General</th></tr>
+    <tr><th>Brand:</th><td>&lrm;Acme&lrm;</td></tr>
+    <tr><th>Color</th><td> Black
+      Matte </td><th>Weight</th><td>1 kg</td></tr>
+    <tr><th>Brand</th><td>Other</td></tr>
+  </table>
+  <dl>
+    <dt>Material</dt><dd>Steel</dd><dd>Glass</dd>
+    <div><dt>Size</dt><dt>Dimensions</dt><dd>10 x 20 cm</dd></div>
+  </dl>
+</div>
+</body></html>`
+
+func TestPageReader_Table(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(tableHtml)
+
+	want := [][]string{
+		{"Model", "Price", "Price", ""},
+		{"Model", "USD", "EUR", ""},
+		{"K1", "10", "9", "new"},
+		{"K1", "n/a", "n/a", "old"},
+		{"", "", "", ""},
+		{"K2", "20 nested", "", ""},
+	}
+	if rows := pr.Table("#missing", "#prices"); !reflect.DeepEqual(rows, want) {
+		t.Errorf("Table() = %q, want %q", rows, want)
+	}
+	if rows := pr.Table("#missing"); rows == nil || len(rows) != 0 {
+		t.Errorf("Table() of missing table = %v", rows)
+	}
+
+	records := pr.TableRecords("body")
+	wantRecords := []map[string]string{
+		{"Model": "K1", "Price USD": "10", "Price EUR": "9", "4": "new"},
+		{"Model": "K1", "Price USD": "n/a", "Price EUR": "n/a", "4": "old"},
+		{"Model": "K2", "Price USD": "20 nested", "Price EUR": "", "4": ""},
+	}
+	if !reflect.DeepEqual(records, wantRecords) {
+		t.Errorf("TableRecords() = %v, want %v", records, wantRecords)
+	}
+}
+
+func TestPageReader_KeyValues(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(tableHtml)
+
+	want := map[string]string{
+		"Brand":      "Acme",
+		"Color":      "Black Matte",
+		"Weight":     "1 kg",
+		"Material":   "Steel, Glass",
+		"Size":       "10 x 20 cm",
+		"Dimensions": "10 x 20 cm",
+	}
+	if pairs := pr.KeyValues("#productDetails"); !reflect.DeepEqual(pairs, want) {
+		t.Errorf("KeyValues() = %v, want %v", pairs, want)
+	}
+	if pairs := pr.KeyValues("#productDetails dl"); len(pairs) != 3 {
+		t.Errorf("KeyValues() of dl = %v", pairs)
+	}
+	if pairs := pr.KeyValues("#missing"); pairs == nil || len(pairs) != 0 {
+		t.Errorf("KeyValues() of missing element = %v", pairs)
+	}
+}
+
+func TestPageReader_TableSpans(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(`<html><body><table>
+<thead><tr><th rowspan="3">Size</th><th>Price</th></tr></thead>
+<tbody>
+  <tr><td rowspan="0">S</td><td>1</td></tr>
+  <tr><td>2</td></tr>
+  <tr><td>3</td></tr>
+</tbody>
+<tbody><tr><td>M</td><td>4</td></tr></tbody>
+</table></body></html>`)
+	want := [][]string{
+		{"Size", "Price"},
+		{"S", "1"},
+		{"S", "2"},
+		{"S", "3"},
+		{"M", "4"},
+	}
+	if rows := pr.Table("table"); !reflect.DeepEqual(rows, want) {
+		t.Errorf("Table() = %q, want %q", rows, want)
+	}
+
+	pr.SetHtml(`<html><body><table><tr><td colspan="2000">a</td></tr><tr><td rowspan="99999">b</td></tr></table></body></html>`)
+	if rows := pr.Table("table"); len(rows) != 2 || len(rows[0]) != maxColspan || rows[1][0] != "b" {
+		t.Errorf("colspan should be clamped to %d, got %d rows", maxColspan, len(rows))
+	}
+}
@@ -0,0 +1,98 @@
+package pagereader
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"math"
+)
+
+//go:embed scripts/timing.js
+var timingScript string
+
+// PageTiming Navigation timing and web vitals, durations are milliseconds
+type PageTiming struct {
+	Redirect             float64 `json:"redirect"`
+	DNS                  float64 `json:"dns"`
+	Connect              float64 `json:"connect"`
+	TLS                  float64 `json:"tls"`
+	TTFB                 float64 `json:"ttfb"`
+	Response             float64 `json:"response"`
+	DOMInteractive       float64 `json:"domInteractive"`
+	DOMContentLoaded     float64 `json:"domContentLoaded"`
+	Load                 float64 `json:"load"`
+	TransferSize         int64   `json:"transferSize"` // bytes of the main document
+	EncodedBodySize      int64   `json:"encodedBodySize"`
+	DecodedBodySize      int64   `json:"decodedBodySize"`
+	ResourceCount        int     `json:"resourceCount"`
+	ResourceTransferSize int64   `json:"resourceTransferSize"` // bytes of all sub-resources
+	FCP                  float64 `json:"fcp"`                  // First Contentful Paint
+	LCP                  float64 `json:"lcp"`                  // Largest Contentful Paint
+	CLS                  float64 `json:"cls"`                  // Cumulative Layout Shift, unitless
+}
+
+func (t PageTiming) String() string {
+	return fmt.Sprintf("TTFB: %.0fms, DOMContentLoaded: %.0fms, Load: %.0fms, FCP: %.0fms, LCP: %.0fms, CLS: %.3f, Resources: %d (%d bytes)",
+		t.TTFB, t.DOMContentLoaded, t.Load, t.FCP, t.LCP, t.CLS, t.ResourceCount, t.ResourceTransferSize)
+}
+
+// SetTimingOnOpen Open will collect navigation timing and web vitals into PageReader.Timing
+func (pr *PageReader) SetTimingOnOpen(collect bool) *PageReader {
+	pr.Config.TimingOnOpen = collect
+	return pr
+}
+
+// newPageTiming Map values of timing script to PageTiming. Durations are 0 instead of negative if the end event has
+// not happened yet, e.g. load of legacy performance.timing is loadEventEnd - navigationStart where loadEventEnd is 0
+func newPageTiming(values map[string]float64) *PageTiming {
+	duration := func(name string) float64 {
+		return math.Max(values[name], 0)
+	}
+	size := func(name string) int64 {
+		return int64(math.Round(math.Max(values[name], 0)))
+	}
+	return &PageTiming{
+		Redirect:             duration("redirect"),
+		DNS:                  duration("dns"),
+		Connect:              duration("connect"),
+		TLS:                  duration("tls"),
+		TTFB:                 duration("ttfb"),
+		Response:             duration("response"),
+		DOMInteractive:       duration("domInteractive"),
+		DOMContentLoaded:     duration("domContentLoaded"),
+		Load:                 duration("load"),
+		TransferSize:         size("transferSize"),
+		EncodedBodySize:      size("encodedBodySize"),
+		DecodedBodySize:      size("decodedBodySize"),
+		ResourceCount:        int(size("resourceCount")),
+		ResourceTransferSize: size("resourceTransferSize"),
+		FCP:                  duration("fcp"),
+		LCP:                  duration("lcp"),
+		CLS:                  duration("cls"),
+	}
+}
+
+func timingTask(timing *PageTiming) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		values := make(map[string]float64)
+		err := chromedp.Evaluate(timingScript, &values, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		}).Do(ctx)
+		if err != nil {
+			return err
+		}
+		*timing = *newPageTiming(values)
+		return nil
+	})
+}
+
+// CollectTiming Collect navigation timing and web vitals of current page
+func (pr *PageReader) CollectTiming(ctx context.Context) (*PageTiming, error) {
+	timing := &PageTiming{}
+	if err := pr.RunTasks(ctx, "CollectTiming", 5, chromedp.Tasks{timingTask(timing)}); err != nil {
+		return nil, err
+	}
+	return timing, nil
+}
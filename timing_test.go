@@ -0,0 +1,45 @@
+package pagereader
+
+import (
+	"context"
+	"errors"
+	"github.com/chromedp/chromedp"
+	"testing"
+)
+
+func TestNewPageTiming(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]float64
+		want   PageTiming
+	}{
+		{"empty", map[string]float64{}, PageTiming{}},
+		{
+			"navigation",
+			map[string]float64{"ttfb": 120.5, "domContentLoaded": 800, "load": 1500, "transferSize": 2048, "resourceCount": 12, "resourceTransferSize": 30000.4, "fcp": 900, "lcp": 1200, "cls": 0.05},
+			PageTiming{TTFB: 120.5, DOMContentLoaded: 800, Load: 1500, TransferSize: 2048, ResourceCount: 12, ResourceTransferSize: 30000, FCP: 900, LCP: 1200, CLS: 0.05},
+		},
+		{
+			"unfinished legacy timing",
+			map[string]float64{"ttfb": 50, "response": -1634567890123, "load": -1634567890000},
+			PageTiming{TTFB: 50},
+		},
+		{"unknown values", map[string]float64{"inp": 200}, PageTiming{}},
+	}
+	for _, test := range tests {
+		if timing := newPageTiming(test.values); *timing != test.want {
+			t.Errorf("%s: newPageTiming() = %+v, want %+v", test.name, *timing, test.want)
+		}
+	}
+}
+
+func TestPageReader_CollectTimingWithoutBrowser(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	timing, err := pr.CollectTiming(context.Background())
+	if !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if timing != nil {
+		t.Errorf("unexpected timing: %+v", timing)
+	}
+}
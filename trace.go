@@ -0,0 +1,67 @@
+package pagereader
+
+import (
+	"context"
+	"net/url"
+)
+
+// Tracer Tracing hook, see the tracing subpackage for OpenTelemetry implementation
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Field) (context.Context, Span)
+}
+
+// SpanContextTracer Optional Tracer extension which carries span context of Open to extraction spans, because
+// extraction helpers have no ctx. Span context must be an immutable value, contexts are not kept by PageReader.
+type SpanContextTracer interface {
+	Tracer
+	SpanContext(ctx context.Context) interface{}
+	ContextWithSpanContext(ctx context.Context, sc interface{}) context.Context
+}
+
+type Span interface {
+	SetAttributes(attrs ...Field)
+	End(err error)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Field) {}
+
+func (noopSpan) End(err error) {}
+
+// SetTracer Open, RunTasks, WaitReady and extraction calls will produce spans, nil to disable
+func (pr *PageReader) SetTracer(tracer Tracer) *PageReader {
+	pr.Tracer = tracer
+	return pr
+}
+
+func (pr PageReader) startSpan(ctx context.Context, name string, attrs ...Field) (context.Context, Span) {
+	if pr.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return pr.Tracer.Start(ctx, name, attrs...)
+}
+
+// spanContextOf Span context in ctx if Tracer supports it
+func (pr PageReader) spanContextOf(ctx context.Context) interface{} {
+	if t, ok := pr.Tracer.(SpanContextTracer); ok {
+		return t.SpanContext(ctx)
+	}
+	return nil
+}
+
+// extractionContext Extraction helpers have no ctx, spans are children of the caller's span of the latest Open
+func (pr PageReader) extractionContext() context.Context {
+	ctx := context.Background()
+	if t, ok := pr.Tracer.(SpanContextTracer); ok && pr.spanContext != nil {
+		ctx = t.ContextWithSpanContext(ctx, pr.spanContext)
+	}
+	return ctx
+}
+
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Host
+	}
+	return ""
+}
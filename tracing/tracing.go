@@ -0,0 +1,116 @@
+// Package tracing OpenTelemetry tracing for page reader, it's a separate module so the core module does not depend on OpenTelemetry.
+//
+//	pr.SetTracer(tracing.NewTracer(nil))
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/hiscaler/pagereader"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+const InstrumentationName = "github.com/hiscaler/pagereader"
+
+// Attribute keys
+const (
+	AttributeURL          = "url.full"
+	AttributeHost         = "server.address"
+	AttributeStatusCode   = "http.response.status_code"
+	AttributeRetryAttempt = "pagereader.retry.attempt"
+	AttributeBackend      = "pagereader.backend"
+	AttributeBytes        = "pagereader.bytes"
+)
+
+// attributeKeys Map page reader field keys to attribute keys
+var attributeKeys = map[string]string{
+	"url":           AttributeURL,
+	"host":          AttributeHost,
+	"status_code":   AttributeStatusCode,
+	"retry.attempt": AttributeRetryAttempt,
+	"backend":       AttributeBackend,
+	"bytes":         AttributeBytes,
+}
+
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer Create tracer from provider, use the global provider if tp is nil
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(InstrumentationName)}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...pagereader.Field) (context.Context, pagereader.Span) {
+	ctx, span := t.tracer.Start(ctx, "pagereader."+name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, &Span{span: span}
+}
+
+// SpanContext Span context in ctx, extraction spans of PageReader are its children
+func (t *Tracer) SpanContext(ctx context.Context) interface{} {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc
+	}
+	return nil
+}
+
+func (t *Tracer) ContextWithSpanContext(ctx context.Context, sc interface{}) context.Context {
+	if sc, ok := sc.(trace.SpanContext); ok {
+		return trace.ContextWithSpanContext(ctx, sc)
+	}
+	return ctx
+}
+
+type Span struct {
+	span trace.Span
+}
+
+func (s *Span) SetAttributes(attrs ...pagereader.Field) {
+	s.span.SetAttributes(attributes(attrs)...)
+}
+
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+func attributes(fields []pagereader.Field) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	for _, field := range fields {
+		key := field.Key
+		if k, ok := attributeKeys[key]; ok {
+			key = k
+		} else {
+			key = "pagereader." + key
+		}
+		switch v := field.Value.(type) {
+		case string:
+			attrs = append(attrs, attribute.String(key, v))
+		case bool:
+			attrs = append(attrs, attribute.Bool(key, v))
+		case int:
+			attrs = append(attrs, attribute.Int(key, v))
+		case int64:
+			attrs = append(attrs, attribute.Int64(key, v))
+		case float64:
+			attrs = append(attrs, attribute.Float64(key, v))
+		case []string:
+			attrs = append(attrs, attribute.StringSlice(key, v))
+		case time.Duration:
+			attrs = append(attrs, attribute.Float64(key, v.Seconds()))
+		default:
+			attrs = append(attrs, attribute.String(key, fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
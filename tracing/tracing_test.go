@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/hiscaler/pagereader"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func newTestTracer() (*Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return NewTracer(tp), exporter
+}
+
+func TestTracer_Extraction(t *testing.T) {
+	tracer, exporter := newTestTracer()
+	pr := pagereader.NewPageReader(10, pagereader.NopLogger())
+	pr.SetTracer(tracer)
+	pr.SetHtml(`<html><body><h1 id="title">Hello</h1></body></html>`)
+	if pr.Text("#title") != "Hello" {
+		t.Fatal("unexpected text")
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "pagereader.Text" {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes {
+		values[kv.Key] = kv.Value
+	}
+	if values["pagereader.matched"].AsBool() != true || values[AttributeBytes].AsInt64() != 5 {
+		t.Errorf("unexpected attributes: %v", spans[0].Attributes)
+	}
+}
+
+func TestTracer_ParentAndError(t *testing.T) {
+	tracer, exporter := newTestTracer()
+	ctx, parent := tracer.Start(context.Background(), "Open", pagereader.F("url", "https://example.com"), pagereader.F("retry.attempt", 1))
+	_, child := tracer.Start(ctx, "RunTasks")
+	child.End(errors.New("timeout"))
+	parent.End(nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Errorf("RunTasks should be child of Open")
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected error status")
+	}
+	if spans[1].Attributes[0].Key != AttributeURL {
+		t.Errorf("unexpected attributes: %v", spans[1].Attributes)
+	}
+}
+
+func TestTracer_SpanContext(t *testing.T) {
+	tracer, exporter := newTestTracer()
+	if sc := tracer.SpanContext(context.Background()); sc != nil {
+		t.Errorf("unexpected span context: %v", sc)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, parent := tracer.Start(ctx, "Caller")
+	sc := tracer.SpanContext(ctx)
+	cancel()
+	parent.End(nil)
+
+	// Span context outlives the cancelled ctx
+	_, child := tracer.Start(tracer.ContextWithSpanContext(context.Background(), sc), "Text")
+	child.End(nil)
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[1].Parent.SpanID() != spans[0].SpanContext.SpanID() {
+		t.Errorf("Text should be child of Caller: %+v", spans)
+	}
+}
@@ -0,0 +1,349 @@
+package pagereader
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFieldRequired Required field matched nothing
+var ErrFieldRequired = errors.New("pagereader: required field not found")
+
+// timeLayouts Layouts tried in order if layout option is not set. Numeric day/month layouts such as "01/02/2006" are
+// ambiguous between regions, so they must be set by layout option.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+}
+
+// FieldError Error of a struct field, Field is the path of field, e.g. Reviews[1].Rating
+type FieldError struct {
+	Field     string
+	Selectors []string
+	Err       error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %s", e.Field, strings.Join(e.Selectors, ", "), e.Err.Error())
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors Errors of all failed fields
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "pagereader: unmarshal failed, " + strings.Join(messages, "; ")
+}
+
+// fieldTag Parsed pr tag, format: selector[@attr][,selector[@attr]...][;option...]
+// Options: required, html, outer_html, exists, count, layout=<time layout>
+// "@attr" suffix is not supported by XPath selectors, select attribute in expression instead, e.g. "xpath://a/@href"
+type fieldTag struct {
+	rule     Rule
+	required bool
+	layout   string
+}
+
+func parseFieldTag(tag string) (t fieldTag, err error) {
+	parts := splitTopLevel(tag, ';')
+	for _, selector := range splitTopLevel(parts[0], ',') {
+		selector = strings.TrimSpace(selector)
+		attr := ""
+		// "@" in attribute selectors such as a[href*="@"] is not the attribute separator
+		if items := splitTopLevel(selector, '@'); len(items) > 1 && !isXPath(selector) {
+			n := len(items) - 1
+			selector, attr = strings.TrimSpace(strings.Join(items[:n], "@")), strings.TrimSpace(items[n])
+			if attr == "" {
+				return t, errors.New("empty attribute name")
+			}
+		}
+		if len(t.rule.Selector) > 0 && attr != t.rule.Attr {
+			return t, errors.New("selectors must use the same attribute")
+		}
+		t.rule.Selector = append(t.rule.Selector, selector)
+		t.rule.Attr = attr
+	}
+	for _, option := range parts[1:] {
+		name, value := splitProcess(strings.Replace(option, "=", ":", 1))
+		switch name {
+		case "required":
+			t.required = true
+		case "layout":
+			t.layout = value
+		case string(ModeHtml), string(ModeOuterHtml), string(ModeExists), string(ModeCount):
+			t.rule.Mode = ExtractMode(name)
+		case "":
+		default:
+			return t, fmt.Errorf("unknown option %s", name)
+		}
+	}
+	return
+}
+
+// splitTopLevel Split s by sep outside of brackets, parentheses and quotes, e.g. "a[title='x,y'],b" => ["a[title='x,y']", "b"]
+func splitTopLevel(s string, sep rune) []string {
+	parts := make([]string, 0)
+	depth := 0
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// Unmarshal Fill struct pointed by v from current document by pr tags, e.g.
+//
+//	type Product struct {
+//		Title   string    `pr:"#productTitle,#title;required"`
+//		Brand   string    `pr:"#bylineInfo@href"`
+//		Price   float64   `pr:".a-price .a-offscreen"`
+//		Images  []string  `pr:"#altImages img@src"`
+//		Reviews []Review  `pr:".review"` // Fields of Review are scoped in every matched element
+//		Date    time.Time `pr:"#date;layout=Jan 2, 2006"`
+//	}
+//
+// Empty selector means the scoped element itself, e.g. `pr:"@href"`.
+// Returned error is FieldErrors if any required field is missing or conversion failed, other fields are still filled.
+func (pr PageReader) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("pagereader: Unmarshal requires a non-nil struct pointer")
+	}
+	var root *goquery.Selection
+	if pr.Doc != nil {
+		root = pr.Doc.Selection
+	}
+	errs := make(FieldErrors, 0)
+	pr.unmarshalStruct(root, rv.Elem(), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	missing := make([]string, 0)
+	for _, e := range errs {
+		if errors.Is(e, ErrFieldRequired) {
+			missing = append(missing, e.Field)
+		}
+	}
+	if len(missing) > 0 {
+		pr.extractionFailed(context.Background(), "Unmarshal", strings.Join(missing, ", "), errs)
+	}
+	return errs
+}
+
+func (pr PageReader) unmarshalStruct(root *goquery.Selection, rv reflect.Value, path string, errs *FieldErrors) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" {
+			continue // Unexported
+		}
+		name := sf.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		tag, ok := sf.Tag.Lookup("pr")
+		if !ok {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				pr.unmarshalStruct(root, rv.Field(i), path, errs)
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+		t, err := parseFieldTag(tag)
+		if err != nil {
+			*errs = append(*errs, &FieldError{Field: name, Selectors: []string{tag}, Err: err})
+			continue
+		}
+		pr.unmarshalField(root, rv.Field(i), name, t, errs)
+	}
+}
+
+func (pr PageReader) unmarshalField(root *goquery.Selection, fv reflect.Value, name string, t fieldTag, errs *FieldErrors) {
+	fail := func(err error) {
+		*errs = append(*errs, &FieldError{Field: name, Selectors: t.rule.Selector, Err: err})
+	}
+	ft := indirectType(fv.Type())
+	rule := t.rule
+	switch {
+	case ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8:
+		rule.Multiple = true
+		if isNestedStruct(indirectType(ft.Elem())) {
+			rule.Items = Schema{}
+		}
+	case isNestedStruct(ft):
+		rule.Items = Schema{}
+	}
+
+	m, elements := pr.matchRule(root, "Unmarshal", name, &rule)
+	switch {
+	case rule.Mode == ModeExists:
+		if err := setValue(fv, strconv.FormatBool(m.Matched()), t.layout); err != nil {
+			fail(err)
+		}
+		return
+	case rule.Mode == ModeCount:
+		if err := setValue(fv, strconv.Itoa(m.Count), t.layout); err != nil {
+			fail(err)
+		}
+		return
+	case !m.Matched():
+		if t.required {
+			fail(ErrFieldRequired)
+		}
+		return
+	}
+
+	fv = allocate(fv)
+	switch {
+	case rule.Items != nil && rule.Multiple:
+		slice := reflect.MakeSlice(fv.Type(), elements.Length(), elements.Length())
+		elements.Each(func(i int, s *goquery.Selection) {
+			pr.unmarshalStruct(s, allocate(slice.Index(i)), fmt.Sprintf("%s[%d]", name, i), errs)
+		})
+		fv.Set(slice)
+	case rule.Items != nil:
+		pr.unmarshalStruct(elements.First(), fv, name, errs)
+	case rule.Multiple:
+		slice := reflect.MakeSlice(fv.Type(), len(m.Values), len(m.Values))
+		for i, value := range m.Values {
+			if err := setValue(slice.Index(i), value, t.layout); err != nil {
+				*errs = append(*errs, &FieldError{Field: fmt.Sprintf("%s[%d]", name, i), Selectors: []string{m.Selector}, Err: err})
+			}
+		}
+		fv.Set(slice)
+	default:
+		if err := setValue(fv, m.Value(), t.layout); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isNestedStruct Whether t is a struct of nested fields rather than a value, e.g. time.Time
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}) && !reflect.PtrTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+// allocate Allocate nil pointers, return the pointed value
+func allocate(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// setValue Convert s and set to v, number conversion uses same rules of int and float processors
+func setValue(v reflect.Value, s, layout string) error {
+	v = allocate(v)
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := parseTime(s, layout)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(numeric(s, false), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(numeric(s, false), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(numeric(s, true), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func parseTime(s, layout string) (t time.Time, err error) {
+	layouts := timeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, layout = range layouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			return
+		}
+	}
+	return
+}
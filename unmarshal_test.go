@@ -0,0 +1,127 @@
+package pagereader
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testReview struct {
+	Author   string  `pr:".author"`
+	Rating   int     `pr:".rating@data-rating"`
+	Verified bool    `pr:".verified;exists"`
+	Comment  *string `pr:".comment"`
+}
+
+type testProduct struct {
+	Title     string       `pr:"#productTitle,h1;required"`
+	Brand     string       `pr:"#bylineInfo@href"`
+	Price     float64      `pr:".price,.sale"`
+	Available bool         `pr:"#availability@data-available"`
+	Images    []string     `pr:"#images img@src"`
+	Reviews   []testReview `pr:".review"`
+	First     *testReview  `pr:".review"`
+	Count     int          `pr:".review;count"`
+	Date      time.Time    `pr:"#date;layout=Jan 2, 2006"`
+	ASIN      string       `pr:"#asin;required"`
+	Ignored   string       `pr:"-"`
+	untagged  string
+}
+
+func TestPageReader_Unmarshal(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(productHtml + `<p id="date">Mar 5, 2022</p>`)
+	product := testProduct{Ignored: "keep"}
+	err := pr.Unmarshal(&product)
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "ASIN" || !errors.Is(errs[0], ErrFieldRequired) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := testProduct{
+		Title:     "Coffee   Maker",
+		Brand:     "/stores/acme",
+		Price:     1299.5,
+		Available: true,
+		Images:    []string{"/1.jpg", "/2.jpg"},
+		Reviews: []testReview{
+			{Author: "Tom", Rating: 5, Verified: true},
+			{Author: "Ann", Rating: 3},
+		},
+		First:   &testReview{Author: "Tom", Rating: 5, Verified: true},
+		Count:   2,
+		Date:    time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC),
+		Ignored: "keep",
+	}
+	if !reflect.DeepEqual(product, expected) {
+		t.Errorf("unexpected product:\n%+v\nexpected:\n%+v", product, expected)
+	}
+}
+
+func TestPageReader_UnmarshalErrors(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(`<div class="item"><a href="/1">one</a></div><div class="item"><a href="/2">2</a></div>`)
+	var v struct {
+		Items []struct {
+			Link  string `pr:"a@href"`
+			Value int    `pr:"a"`
+		} `pr:".item"`
+		Bad string `pr:"a@href,a@title"`
+	}
+	err := pr.Unmarshal(&v)
+	var errs FieldErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "Items[0].Value" || errs[1].Field != "Bad" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(v.Items) != 2 || v.Items[1].Link != "/2" || v.Items[1].Value != 2 {
+		t.Errorf("unexpected items: %+v", v.Items)
+	}
+	if err = pr.Unmarshal(v); err == nil {
+		t.Error("expected error of non-pointer value")
+	}
+}
+
+func TestParseFieldTag(t *testing.T) {
+	tag, err := parseFieldTag("#a@href, #b @ href;required;layout=2006-01-02")
+	if err != nil {
+		t.Fatalf("parse tag failed: %s", err.Error())
+	}
+	if !reflect.DeepEqual(tag.rule.Selector, Selectors{"#a", "#b"}) || tag.rule.Attr != "href" || !tag.required || tag.layout != "2006-01-02" {
+		t.Errorf("unexpected tag: %+v", tag)
+	}
+	tag, err = parseFieldTag(`a[href*="@"], a[href^='mailto:']`)
+	if err != nil {
+		t.Fatalf("parse tag failed: %s", err.Error())
+	}
+	if !reflect.DeepEqual(tag.rule.Selector, Selectors{`a[href*="@"]`, "a[href^='mailto:']"}) || tag.rule.Attr != "" {
+		t.Errorf("unexpected tag: %+v", tag)
+	}
+	tag, err = parseFieldTag(`a[href*="@"]@href`)
+	if err != nil || !reflect.DeepEqual(tag.rule.Selector, Selectors{`a[href*="@"]`}) || tag.rule.Attr != "href" {
+		t.Errorf("unexpected tag: %+v, error: %v", tag, err)
+	}
+	if _, err = parseFieldTag("#a;unknown"); err == nil {
+		t.Error("expected error of unknown option")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		s, layout string
+		expected  time.Time
+		ok        bool
+	}{
+		{"2024-03-04", "", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), true},
+		{"Mar 4, 2024", "", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), true},
+		{"4 March 2024", "", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), true},
+		{"03/04/2024", "", time.Time{}, false},
+		{"03/04/2024", "01/02/2006", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), true},
+		{"03/04/2024", "02/01/2006", time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC), true},
+	}
+	for _, test := range tests {
+		v, err := parseTime(test.s, test.layout)
+		if (err == nil) != test.ok || !v.Equal(test.expected) {
+			t.Errorf("parseTime(%q, %q) = %v, %v", test.s, test.layout, v, err)
+		}
+	}
+}
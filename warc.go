@@ -0,0 +1,361 @@
+package pagereader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	WARCVersion         = "WARC/1.1"
+	defaultWARCMaxSize  = 1 << 30 // 1GB
+	warcSoftware        = "pagereader"
+	warcDateFormat      = "2006-01-02T15:04:05.000000Z"
+	warcFileTimeFormat  = "20060102150405"
+	warcHTMLContentType = "text/html; charset=utf-8"
+)
+
+const (
+	WARCTypeInfo     = "warcinfo"
+	WARCTypeRequest  = "request"
+	WARCTypeResponse = "response"
+	WARCTypeMetadata = "metadata"
+	WARCTypeResource = "resource"
+)
+
+// WARCRecord A single WARC record, WARC-Record-ID, WARC-Date, Content-Length and WARC-Block-Digest are filled by writer if empty
+type WARCRecord struct {
+	Type    string
+	Headers [][2]string // Extra named fields
+	Block   []byte
+	ID      string
+	Date    time.Time
+}
+
+// WARCWriter Write WARC 1.1 files, every record is compressed as a separate gzip member,
+// file will be rotated when it's size exceeds MaxSize
+type WARCWriter struct {
+	Dir     string
+	Prefix  string
+	MaxSize int64 // bytes, default 1GB
+
+	mu       sync.Mutex
+	file     *os.File
+	filename string
+	size     int64
+	serial   int
+}
+
+func NewWARCWriter(dir, prefix string, maxSize int64) *WARCWriter {
+	if prefix == "" {
+		prefix = warcSoftware
+	}
+	if maxSize <= 0 {
+		maxSize = defaultWARCMaxSize
+	}
+	return &WARCWriter{Dir: dir, Prefix: prefix, MaxSize: maxSize}
+}
+
+// Filename Current WARC file name
+func (w *WARCWriter) Filename() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.filename
+}
+
+func (w *WARCWriter) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+	if err := os.MkdirAll(w.Dir, 0755); err != nil {
+		return err
+	}
+	w.serial++
+	filename := filepath.Join(w.Dir, fmt.Sprintf("%s-%s-%05d.warc.gz", w.Prefix, time.Now().UTC().Format(warcFileTimeFormat), w.serial))
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.filename = filename
+	w.size = 0
+	block := []byte(fmt.Sprintf("software: %s\r\nformat: WARC File Format 1.1\r\nconformsTo: http://iipc.github.io/warc-specifications/specifications/warc-format/warc-1.1/\r\n", warcSoftware))
+	return w.write(&WARCRecord{
+		Type:    WARCTypeInfo,
+		Headers: [][2]string{{"WARC-Filename", filepath.Base(filename)}, {"Content-Type", "application/warc-fields"}},
+		Block:   block,
+	})
+}
+
+// write Write gzip compressed record to current file
+func (w *WARCWriter) write(r *WARCRecord) error {
+	if r.ID == "" {
+		r.ID = newWARCRecordID()
+	}
+	if r.Date.IsZero() {
+		r.Date = time.Now()
+	}
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	headers := append([][2]string{
+		{"WARC-Type", r.Type},
+		{"WARC-Record-ID", r.ID},
+		{"WARC-Date", r.Date.UTC().Format(warcDateFormat)},
+	}, r.Headers...)
+	headers = append(headers, [2]string{"WARC-Block-Digest", warcDigest(r.Block)}, [2]string{"Content-Length", strconv.Itoa(len(r.Block))})
+	io.WriteString(zw, WARCVersion+"\r\n")
+	writeHTTPHeaders(zw, headers)
+	zw.Write(r.Block)
+	io.WriteString(zw, "\r\n\r\n")
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	n, err := w.file.Write(b.Bytes())
+	w.size += int64(n)
+	return err
+}
+
+// WriteRecords Write records into the same file
+func (w *WARCWriter) WriteRecords(records ...*WARCRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil || w.size >= w.MaxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	for _, r := range records {
+		if err := w.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePage Write request, response, metadata and rendered DOM resource records of the page
+func (w *WARCWriter) WritePage(pr *PageReader) error {
+	if pr.URL == "" {
+		return errors.New("pagereader: page URL is empty")
+	}
+	records := make([]*WARCRecord, 0, 4)
+	targetURI := pr.URL
+	date := time.Now()
+	var concurrentTo string
+	if d := pr.Document; d != nil && d.Request != nil {
+		targetURI = d.Request.URL
+		date = d.Time
+		request := &WARCRecord{
+			Type:    WARCTypeRequest,
+			Date:    date,
+			ID:      newWARCRecordID(),
+			Headers: [][2]string{{"WARC-Target-URI", targetURI}, {"Content-Type", "application/http;msgtype=request"}},
+			Block:   httpRequestBlock(d),
+		}
+		records = append(records, request)
+		concurrentTo = request.ID
+		if d.Response != nil {
+			response := &WARCRecord{
+				Type:  WARCTypeResponse,
+				Date:  date,
+				ID:    newWARCRecordID(),
+				Block: httpResponseBlock(d),
+				Headers: [][2]string{
+					{"WARC-Target-URI", targetURI},
+					{"Content-Type", "application/http;msgtype=response"},
+				},
+			}
+			if d.BodyTruncated {
+				response.Headers = append(response.Headers, [2]string{"WARC-Truncated", "unspecified"})
+			} else {
+				response.Headers = append(response.Headers, [2]string{"WARC-Payload-Digest", warcDigest(d.Body)})
+			}
+			if d.Response.RemoteIPAddress != "" {
+				response.Headers = append(response.Headers, [2]string{"WARC-IP-Address", d.Response.RemoteIPAddress})
+			}
+			request.Headers = append(request.Headers, [2]string{"WARC-Concurrent-To", response.ID})
+			records = append(records, response)
+			concurrentTo = response.ID
+		}
+	}
+
+	metadata := &WARCRecord{
+		Type:    WARCTypeMetadata,
+		Date:    date,
+		Headers: [][2]string{{"WARC-Target-URI", targetURI}, {"Content-Type", "application/warc-fields"}},
+		Block:   warcFields(pr),
+	}
+	resource := &WARCRecord{
+		Type:    WARCTypeResource,
+		Date:    date,
+		Headers: [][2]string{{"WARC-Target-URI", "urn:pagereader:dom:" + targetURI}, {"Content-Type", warcHTMLContentType}},
+		Block:   []byte(pr.Html()),
+	}
+	if concurrentTo != "" {
+		metadata.Headers = append(metadata.Headers, [2]string{"WARC-Refers-To", concurrentTo})
+		resource.Headers = append(resource.Headers, [2]string{"WARC-Concurrent-To", concurrentTo})
+	}
+	records = append(records, metadata, resource)
+	return w.WriteRecords(records...)
+}
+
+func (w *WARCWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func warcFields(pr *PageReader) []byte {
+	b := &bytes.Buffer{}
+	fields := [][2]string{
+		{"software", warcSoftware},
+		{"title", pr.Title},
+		{"console-messages", strconv.Itoa(len(pr.ConsoleMessages))},
+		{"exceptions", strconv.Itoa(len(pr.Exceptions()))},
+		{"dialogs", strconv.Itoa(len(pr.Dialogs))},
+	}
+	if pr.Error != nil {
+		fields = append(fields, [2]string{"error", pr.Error.Error()})
+	}
+	for _, field := range fields {
+		b.WriteString(field[0] + ": " + strings.NewReplacer("\r", " ", "\n", " ").Replace(field[1]) + "\r\n")
+	}
+	return b.Bytes()
+}
+
+func httpRequestBlock(d *Document) []byte {
+	b := &bytes.Buffer{}
+	method := d.Request.Method
+	if method == "" {
+		method = "GET"
+	}
+	requestURI := "/"
+	host := ""
+	if u, err := url.Parse(d.Request.URL); err == nil {
+		requestURI = u.RequestURI()
+		host = u.Host
+	}
+	b.WriteString(method + " " + requestURI + " HTTP/1.1\r\n")
+	headers := httpHeaders(d.Request.Headers)
+	if host != "" && !hasHTTPHeader(headers, "Host") {
+		headers = append([][2]string{{"Host", host}}, headers...)
+	}
+	writeHTTPHeaders(b, headers)
+	b.WriteString(d.Request.PostData)
+	return b.Bytes()
+}
+
+func httpResponseBlock(d *Document) []byte {
+	b := &bytes.Buffer{}
+	statusText := d.Response.StatusText
+	if statusText == "" {
+		statusText = http.StatusText(int(d.Response.Status))
+	}
+	b.WriteString(fmt.Sprintf("HTTP/1.1 %d %s\r\n", d.Response.Status, statusText))
+	headers := make([][2]string, 0)
+	for _, h := range httpHeaders(d.Response.Headers) {
+		// Body is decoded by Chrome
+		switch strings.ToLower(h[0]) {
+		case "content-encoding", "transfer-encoding", "content-length":
+			continue
+		}
+		headers = append(headers, h)
+	}
+	headers = append(headers, [2]string{"Content-Length", strconv.Itoa(len(d.Body))})
+	writeHTTPHeaders(b, headers)
+	b.Write(d.Body)
+	return b.Bytes()
+}
+
+// httpHeaders Sorted headers, Chrome joins values of same header with "\n"
+func httpHeaders(headers map[string]interface{}) [][2]string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		if strings.HasPrefix(k, ":") {
+			continue // HTTP/2 pseudo headers
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([][2]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range strings.Split(fmt.Sprint(headers[k]), "\n") {
+			values = append(values, [2]string{k, v})
+		}
+	}
+	return values
+}
+
+func hasHTTPHeader(headers [][2]string, name string) bool {
+	for _, h := range headers {
+		if strings.EqualFold(h[0], name) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHTTPHeaders(w io.Writer, headers [][2]string) {
+	for _, h := range headers {
+		io.WriteString(w, h[0]+": "+h[1]+"\r\n")
+	}
+	io.WriteString(w, "\r\n")
+}
+
+func warcDigest(b []byte) string {
+	sum := sha1.Sum(b)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+func newWARCRecordID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// SetWARCWriter Open will write WARC records of every page to w, nil to disable
+func (pr *PageReader) SetWARCWriter(w *WARCWriter) *PageReader {
+	pr.Config.WARC = w
+	return pr
+}
+
+func (pr *PageReader) writeWARC(ctx context.Context, notify *Notify, timeout int) {
+	if pr.Config.WARC == nil {
+		return
+	}
+	step := notify.Child("WARC", pr.URL)
+	if err := pr.Document.fetchBody(ctx, timeout); err != nil {
+		notify.AddLogf("Fetch response body failed, error: %s", err.Error())
+	}
+	err := pr.Config.WARC.WritePage(pr)
+	step.Finish(err)
+	if err != nil {
+		notify.AddLogf("Write WARC failed, error: %s", err.Error())
+	} else {
+		notify.AddLogf("WARC: %s", pr.Config.WARC.Filename())
+	}
+}
@@ -0,0 +1,118 @@
+package pagereader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"github.com/chromedp/cdproto/network"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readWARCTypes(t *testing.T, filename string) []string {
+	types := make([]string, 0)
+	scanner := bufio.NewScanner(strings.NewReader(readWARC(t, filename)))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "WARC-Type: ") {
+			types = append(types, strings.TrimPrefix(line, "WARC-Type: "))
+		}
+	}
+	return types
+}
+
+func readWARC(t *testing.T, filename string) string {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestWARCWriter_WritePage(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWARCWriter(dir, "test", 1)
+	pr := NewPageReader(10, NopLogger())
+	pr.URL = "https://example.com/a?b=1"
+	pr.Title = "Example"
+	pr.SetHtml("<html><body>Hello</body></html>")
+	pr.Document = &Document{
+		Request: &network.Request{
+			URL:     pr.URL,
+			Method:  "GET",
+			Headers: network.Headers{"User-Agent": "test"},
+		},
+		Response: &network.Response{
+			Status:  200,
+			Headers: network.Headers{"Content-Type": "text/html", "Content-Encoding": "gzip", "Set-Cookie": "a=1\nb=2"},
+		},
+		Time: time.Now(),
+		Body: []byte("<html><body>Hello</body></html>"),
+	}
+	if err := w.WritePage(pr); err != nil {
+		t.Fatal(err)
+	}
+	first := w.Filename()
+	if err := w.WritePage(pr); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if first == w.Filename() {
+		t.Errorf("WARC file should be rotated")
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "test-*.warc.gz"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	types := strings.Join(readWARCTypes(t, first), ",")
+	if types != "warcinfo,request,response,metadata,resource" {
+		t.Errorf("unexpected record types: %s", types)
+	}
+}
+
+func TestWARCWriter_WritePageTruncated(t *testing.T) {
+	w := NewWARCWriter(t.TempDir(), "test", 0)
+	pr := NewPageReader(10, NopLogger())
+	pr.URL = "https://example.com/"
+	pr.Document = &Document{
+		Request:       &network.Request{URL: pr.URL, Method: "GET"},
+		Response:      &network.Response{Status: 200},
+		BodyTruncated: true,
+	}
+	if err := w.WritePage(pr); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	content := readWARC(t, w.Filename())
+	if strings.Contains(content, "WARC-Payload-Digest") || !strings.Contains(content, "WARC-Truncated: unspecified") {
+		t.Errorf("response record of unavailable body should be marked truncated without payload digest")
+	}
+}
+
+func TestHTTPResponseBlock(t *testing.T) {
+	block := string(httpResponseBlock(&Document{
+		Response: &network.Response{
+			Status:  200,
+			Headers: network.Headers{"Content-Encoding": "gzip", "Set-Cookie": "a=1\nb=2"},
+		},
+		Body: []byte("abc"),
+	}))
+	expected := "HTTP/1.1 200 OK\r\nSet-Cookie: a=1\r\nSet-Cookie: b=2\r\nContent-Length: 3\r\n\r\nabc"
+	if block != expected {
+		t.Errorf("unexpected response block: %q", block)
+	}
+}
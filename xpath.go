@@ -0,0 +1,110 @@
+package pagereader
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// XPathPrefix Selectors with this prefix are evaluated as XPath expressions, e.g. "xpath://th[.='Brand']/following-sibling::td"
+const XPathPrefix = "xpath:"
+
+// XPath Selector of XPath expression, can be mixed with CSS selectors in a fallback chain, e.g.
+// pr.Text(XPath("//th[.='Brand']/following-sibling::td"), "#brand")
+func XPath(expr string) string {
+	return XPathPrefix + expr
+}
+
+func isXPath(selector string) bool {
+	return strings.HasPrefix(selector, XPathPrefix)
+}
+
+var xpathExprs sync.Map // Compiled expressions
+
+// compiledXPath Evaluate mutates state of expression, so it's only used to evaluate node-set expressions by Select
+type compiledXPath struct {
+	expr    *xpath.Expr
+	nodeSet bool // Result is node-set rather than string, number or boolean
+}
+
+func compileXPath(expr string) (*compiledXPath, error) {
+	if v, ok := xpathExprs.Load(expr); ok {
+		return v.(*compiledXPath), nil
+	}
+	compiled, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	probe, _ := xpath.Compile(expr)
+	_, nodeSet := probe.Evaluate(htmlquery.CreateXPathNavigator(&html.Node{Type: html.DocumentNode})).(*xpath.NodeIterator)
+	c := &compiledXPath{expr: compiled, nodeSet: nodeSet}
+	xpathExprs.Store(expr, c)
+	return c, nil
+}
+
+// ValidateSelector Check XPath selector is valid, CSS selectors are always valid
+func ValidateSelector(selector string) error {
+	if !isXPath(selector) {
+		return nil
+	}
+	_, err := compileXPath(strings.TrimPrefix(selector, XPathPrefix))
+	return err
+}
+
+// findXPath Evaluate expr with every node of root as context node, use ".//" to search descendants only.
+// Attribute nodes and string, number or true results are returned as text nodes, invalid expr matches nothing.
+func findXPath(root *goquery.Selection, expr string) *goquery.Selection {
+	// Nodes of Slice result share backing array with root, never append to it
+	s := root.Slice(0, 0)
+	s.Nodes = nil
+	compiled, err := compileXPath(expr)
+	if err != nil {
+		return s
+	}
+	nodes := make([]*html.Node, 0)
+	for _, n := range root.Nodes {
+		if compiled.nodeSet {
+			iterator := compiled.expr.Select(htmlquery.CreateXPathNavigator(n))
+			for iterator.MoveNext() {
+				nav := iterator.Current().(*htmlquery.NodeNavigator)
+				if nav.NodeType() == xpath.AttributeNode {
+					nodes = append(nodes, textElement(nav.LocalName(), nav.Value()))
+				} else {
+					nodes = append(nodes, nav.Current())
+				}
+			}
+			continue
+		}
+		e, _ := xpath.Compile(expr)
+		switch v := e.Evaluate(htmlquery.CreateXPathNavigator(n)).(type) {
+		case string:
+			if v != "" {
+				nodes = append(nodes, textElement("", v))
+			}
+		case float64:
+			nodes = append(nodes, textElement("", strconv.FormatFloat(v, 'f', -1, 64)))
+		case bool:
+			if v {
+				nodes = append(nodes, textElement("", "true"))
+			}
+		}
+	}
+	seen := make(map[*html.Node]bool, len(nodes))
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			s.Nodes = append(s.Nodes, n)
+		}
+	}
+	return s
+}
+
+// textElement Detached element which has a text child only
+func textElement(name, text string) *html.Node {
+	child := &html.Node{Type: html.TextNode, Data: text}
+	return &html.Node{Type: html.ElementNode, Data: name, FirstChild: child, LastChild: child}
+}
@@ -0,0 +1,72 @@
+package pagereader
+
+import (
+	"reflect"
+	"testing"
+)
+
+const detailTableHtml = `<html><body>
+<table id="details">
+<tr><th>Brand</th><td> Acme </td></tr>
+<tr><th>Weight</th><td>1.5 kg</td></tr>
+</table>
+<p><b>Model:</b> CM-100</p>
+<a class="link" href="/a">A</a><a class="link" href="/b" title="x,y">B</a>
+</body></html>`
+
+func TestPageReader_XPath(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(detailTableHtml)
+	if v := pr.Text(XPath("//th[.='Brand']/following-sibling::td")); v != "Acme" {
+		t.Errorf("unexpected text: %s", v)
+	}
+	if v := pr.Text(XPath("//b[.='Model:']/following-sibling::text()")); v != "CM-100" {
+		t.Errorf("unexpected text: %s", v)
+	}
+	if v := pr.Text(XPath("normalize-space(substring-after(//p, ':'))")); v != "CM-100" {
+		t.Errorf("unexpected text: %s", v)
+	}
+	if v := pr.Text(XPath("//th[.='Color']/following-sibling::td"), "#details td"); v != "Acme 1.5 kg" {
+		t.Errorf("unexpected text: %s", v)
+	}
+	if v, exists := pr.Attr(XPath("//a[.='B']"), "title"); !exists || v != "x,y" {
+		t.Errorf("unexpected attr: %s, %v", v, exists)
+	}
+	if values := pr.TextAll(XPath("//a[@class='link']/@href")); !reflect.DeepEqual(values, []string{"/a", "/b"}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if n := pr.Count(XPath("//tr")); n != 2 {
+		t.Errorf("unexpected count: %d", n)
+	}
+	if pr.Exists(XPath("//tr[")) {
+		t.Error("invalid expression should match nothing")
+	}
+
+	rows := make([]string, 0)
+	pr.Each(XPath("//tr"), func(e *Element) {
+		rows = append(rows, e.Text(XPath("./td")))
+	})
+	if !reflect.DeepEqual(rows, []string{"Acme", "1.5 kg"}) {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
+
+func TestPageReader_XPathUnmarshal(t *testing.T) {
+	pr := NewPageReader(10, NopLogger())
+	pr.SetHtml(detailTableHtml)
+	var v struct {
+		Brand  string   `pr:"xpath://th[.='Brand']/following-sibling::td,#brand"`
+		Weight float64  `pr:"xpath://th[contains(., 'Weight')]/../td"`
+		Links  []string `pr:"xpath://a/@href"`
+		Title  string   `pr:"a[title='x,y']@title"`
+	}
+	if err := pr.Unmarshal(&v); err != nil {
+		t.Fatalf("unmarshal failed: %s", err.Error())
+	}
+	if v.Brand != "Acme" || v.Weight != 1.5 || !reflect.DeepEqual(v.Links, []string{"/a", "/b"}) || v.Title != "x,y" {
+		t.Errorf("unexpected value: %+v", v)
+	}
+	if _, err := ParseSchema([]byte(`brand: "xpath://th["`)); err == nil {
+		t.Error("expected error of invalid XPath")
+	}
+}